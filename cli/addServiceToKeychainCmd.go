@@ -1,12 +1,21 @@
 package cli
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	"github.com/spf13/cobra"
 )
 
+// KeychainUpdate is the result of the commands adding or removing a service of a keychain
+type KeychainUpdate struct {
+	Service string `json:"service"`
+	// DerivationPath is the derivation path of an added service
+	DerivationPath string `json:"derivation_path,omitempty"`
+	Status         string `json:"status"`
+	feedback       string
+}
+
 func GetAddServiceToKeychainCmd() *cobra.Command {
 	addServiceToKeychainCmd := &cobra.Command{
 		Use:   "add-service-to-keychain",
@@ -15,18 +24,19 @@ func GetAddServiceToKeychainCmd() *cobra.Command {
 			serviceName, _ := cmd.Flags().GetString("service-name")
 			derivationPath, _ := cmd.Flags().GetString("derivation-path")
 			err := validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
-			cobra.CheckErr(err)
-			accessSeedBytes, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
-			cobra.CheckErr(err)
+			CheckError(err)
+			accessSigner, err := tuiutils.GetSigner(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			CheckError(err)
 
 			// set default derivation path if not set
 			if !cmd.Flag("derivation-path").Changed {
 				derivationPath = "m/650'/" + serviceName + "/0"
 			}
 
-			feedback, err := tuiutils.AddServiceToKeychain(accessSeedBytes, endpoint.String(), serviceName, derivationPath)
-			cobra.CheckErr(err)
-			fmt.Println(feedback)
+			waitConfirmations, timeout := getConfirmationFlags(cmd, tuiutils.AllConfirmations)
+			feedback, err := tuiutils.AddServiceToKeychain(accessSigner, endpoint.String(), serviceName, derivationPath, waitConfirmations, timeout)
+			checkSendError(err)
+			printResult(newKeychainUpdate(serviceName, derivationPath, feedback, waitConfirmations))
 		},
 	}
 
@@ -42,5 +52,21 @@ func GetAddServiceToKeychainCmd() *cobra.Command {
 	addServiceToKeychainCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh")
 	addServiceToKeychainCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh-path")
 	addServiceToKeychainCmd.MarkFlagsMutuallyExclusive("mnemonic", "access-seed")
+	setupKeystoreFlag(addServiceToKeychainCmd)
+	setupMnemonicFlags(addServiceToKeychainCmd)
+	setupSignerFlag(addServiceToKeychainCmd)
+	setupConfirmationFlags(addServiceToKeychainCmd, "default to all confirmations")
 	return addServiceToKeychainCmd
 }
+
+func newKeychainUpdate(serviceName string, derivationPath string, feedback string, waitConfirmations uint) KeychainUpdate {
+	status := tuiutils.TransactionSent
+	if waitConfirmations > 0 {
+		status = tuiutils.TransactionConfirmed
+	}
+	return KeychainUpdate{Service: serviceName, DerivationPath: derivationPath, Status: status.String(), feedback: feedback}
+}
+
+func (u KeychainUpdate) describe() string {
+	return strings.TrimPrefix(u.feedback, "\n") + "\n"
+}
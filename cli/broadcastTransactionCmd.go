@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+)
+
+func GetBroadcastTransactionCmd() *cobra.Command {
+	broadcastTransactionCmd := &cobra.Command{
+		Use:   "broadcast-transaction <file>",
+		Short: "Broadcast a transaction signed with sign-transaction",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			transaction, err := tuiutils.ReadTransactionFile(args[0])
+			CheckError(err)
+
+			waitConfirmations, timeout := getConfirmationFlags(cmd, 0)
+			explorerUrl, err := tuiutils.BroadcastTransaction(transaction, endpoint.String(), waitConfirmations, timeout)
+			checkSendError(err)
+			printResult(newSentTransaction(transaction, explorerUrl, waitConfirmations))
+		},
+	}
+
+	broadcastTransactionCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url])")
+	setupConfirmationFlags(broadcastTransactionCmd, "default to 0, only wait until the transaction is sent")
+	return broadcastTransactionCmd
+}
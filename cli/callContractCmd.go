@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+)
+
+func GetCallContractCmd() *cobra.Command {
+	callContractCmd := &cobra.Command{
+		Use:   "call-contract <address> <action> [args...]",
+		Short: "Call a named action of a smart contract",
+		Long: `Call a named action of a smart contract.
+
+The arguments are converted to JSON values: numbers, true, false, null, quoted strings, JSON arrays and
+objects are kept, anything else is a string. A prefix forces the type of an argument: str:, int:, float:, bool: or json:
+(for instance str:42 is the string "42").`,
+		Args: cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			address, actionName, rawArgs := args[0], args[1], args[2:]
+			if _, err := hex.DecodeString(address); err != nil {
+				CheckError(fmt.Errorf("invalid contract address %q: %w", address, err))
+			}
+
+			configuredTransaction, _ := loadTransactionConfiguration(cmd)
+
+			code, err := tuiutils.GetContractCode(endpoint.String(), address)
+			CheckError(err)
+			action, err := findContractAction(code, actionName)
+			CheckError(err)
+			if len(rawArgs) != len(action.Parameters) {
+				CheckError(fmt.Errorf("the action %s expects %d arguments (%s), got %d", action.Name, len(action.Parameters), strings.Join(action.Parameters, ", "), len(rawArgs)))
+			}
+
+			contractArgs := make([]interface{}, len(rawArgs))
+			for i, rawArg := range rawArgs {
+				contractArgs[i], err = parseContractArg(rawArg)
+				if err != nil {
+					CheckError(fmt.Errorf("argument %s: %w", action.Parameters[i], err))
+				}
+			}
+			argsJson, err := json.Marshal(contractArgs)
+			CheckError(err)
+			configuredTransaction.recipients = append(configuredTransaction.recipients, Recipient{
+				Address:  address,
+				Action:   action.Name,
+				ArgsJson: string(argsJson),
+			})
+
+			txType, err := transactionType.GetTransactionType()
+			CheckError(err)
+			transaction, secretKey, curve := buildConfiguredTransaction(configuredTransaction, txType)
+
+			simulate, _ := cmd.Flags().GetBool("simulate")
+			if simulate {
+				printResult(runTransactionAction(cmd, transaction, secretKey, curve, configuredTransaction, simulateAndSendAction(cmd)))
+			} else {
+				printResult(runTransactionAction(cmd, transaction, secretKey, curve, configuredTransaction, sendTransactionAction(cmd)))
+			}
+		},
+	}
+
+	setupTransactionFlags(callContractCmd)
+	callContractCmd.Flags().Bool("simulate", false, "Simulate the execution of the contract before sending the transaction, which is not sent if the contract rejects it")
+	setupFeeCheckFlags(callContractCmd)
+	setupConfirmationFlags(callContractCmd, "default to 0, only wait until the transaction is sent")
+	return callContractCmd
+}
+
+// findContractAction returns the named action of the contract code
+func findContractAction(code string, name string) (tuiutils.ContractAction, error) {
+	if code == "" {
+		return tuiutils.ContractAction{}, errors.New("there is no smart contract at this address")
+	}
+	actions := tuiutils.ParseContractActions(code)
+	names := make([]string, len(actions))
+	for i, action := range actions {
+		if action.Name == name {
+			return action, nil
+		}
+		names[i] = action.Name
+	}
+	if len(names) == 0 {
+		return tuiutils.ContractAction{}, errors.New("the smart contract doesn't have any named action")
+	}
+	return tuiutils.ContractAction{}, fmt.Errorf("the smart contract doesn't have the action %s (available actions: %s)", name, strings.Join(names, ", "))
+}
+
+// parseContractArg converts a command line argument to a JSON value, see the help of the command
+func parseContractArg(arg string) (interface{}, error) {
+	prefix, value, found := strings.Cut(arg, ":")
+	if found {
+		switch prefix {
+		case "str":
+			return value, nil
+		case "int":
+			if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid integer %q", value)
+			}
+			return json.Number(value), nil
+		case "float":
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return nil, fmt.Errorf("invalid number %q", value)
+			}
+			return json.Number(value), nil
+		case "bool":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid boolean %q", value)
+			}
+			return b, nil
+		case "json":
+			parsed, err := decodeJsonValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSON %q: %w", value, err)
+			}
+			return parsed, nil
+		}
+	}
+
+	if parsed, err := decodeJsonValue(arg); err == nil {
+		return parsed, nil
+	}
+	return arg, nil
+}
+
+// decodeJsonValue decodes a single JSON value, keeping the numbers as they are written
+func decodeJsonValue(value string) (interface{}, error) {
+	if !json.Valid([]byte(value)) {
+		return nil, errors.New("invalid JSON value")
+	}
+	d := json.NewDecoder(strings.NewReader(value))
+	d.UseNumber()
+	var parsed interface{}
+	err := d.Decode(&parsed)
+	return parsed, err
+}
+
+// simulateAndSendAction sends the transaction only if the node accepts the execution of the contract
+func simulateAndSendAction(cmd *cobra.Command) transactionAction {
+	return func(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, index uint, serviceName string, storageNouncePublicKey string, signer tuiutils.Signer) (commandResult, error) {
+		err := tuiutils.SimulateTransaction(transaction, secretKey, curve, serviceMode, endpoint, index, serviceName, storageNouncePublicKey, signer)
+		if err != nil {
+			return nil, err
+		}
+		printMessage("Contract simulation succeeded\n")
+		checks, err := getTransactionChecks(cmd)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tuiutils.CheckTransaction(transaction, endpoint, checks); err != nil {
+			return nil, err
+		}
+		waitConfirmations, timeout := getConfirmationFlags(cmd, 0)
+		explorerUrl, err := tuiutils.BroadcastTransaction(transaction, endpoint, waitConfirmations, timeout)
+		if err != nil {
+			return nil, err
+		}
+		return newSentTransaction(transaction, explorerUrl, waitConfirmations), nil
+	}
+}
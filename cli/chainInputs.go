@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+)
+
+// chainInput is a chain passed by one of its addresses, a seed or a keychain service
+type chainInput struct {
+	label   string
+	resolve func() (string, error)
+	// deriveAddress is only available for the chains of a seed or a keychain service
+	deriveAddress func(index uint) (string, error)
+}
+
+// newDerivedChainInput returns a chain whose addresses can be derived, its last address is
+// the address of its chain length index
+func newDerivedChainInput(label string, deriveAddress func(index uint) (string, error)) chainInput {
+	return chainInput{
+		label:         label,
+		deriveAddress: deriveAddress,
+		resolve: func() (string, error) {
+			genesisAddress, err := deriveAddress(0)
+			if err != nil {
+				return "", err
+			}
+			client := archethic.NewAPIClient(endpoint.String())
+			return deriveAddress(client.GetLastTransactionIndex(genesisAddress))
+		},
+	}
+}
+
+func setupChainInputFlags(cmd *cobra.Command) {
+	cmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url])")
+	cmd.Flags().StringArray("address", []string{}, "Address of the chain (can be passed several times)")
+	cmd.Flags().String("access-seed", "", "Access Seed")
+	cmd.Flags().Bool("ssh", false, "Enable SSH key mode")
+	cmd.Flags().String("ssh-path", GetFirstSshKeyDefaultPath(), "Path to ssh key")
+	cmd.Flags().Bool("mnemonic", false, "Enable mnemonic words for seed")
+	cmd.MarkFlagsMutuallyExclusive("access-seed", "ssh")
+	cmd.MarkFlagsMutuallyExclusive("access-seed", "ssh-path")
+	cmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh")
+	cmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh-path")
+	cmd.MarkFlagsMutuallyExclusive("mnemonic", "access-seed")
+	setupKeystoreFlag(cmd)
+	setupMnemonicFlags(cmd)
+	cmd.Flags().Var(&ellipticCurve, "elliptic-curve", "Elliptic Curve (ED25519|P256|SECP256K1)")
+	cmd.Flags().StringArray("serviceName", []string{}, "Service Name of the keychain of the seed (can be passed several times)")
+}
+
+// getChainInputs returns the chains passed by flags: the addresses, then the seed or the keychain services
+func getChainInputs(cmd *cobra.Command) ([]chainInput, error) {
+	var inputs []chainInput
+	addresses, _ := cmd.Flags().GetStringArray("address")
+	for _, address := range addresses {
+		if _, err := hex.DecodeString(address); err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", address, err)
+		}
+		address := address
+		inputs = append(inputs, chainInput{
+			label: "address " + strings.ToUpper(address),
+			resolve: func() (string, error) {
+				return tuiutils.GetLastTransactionAddress(endpoint.String(), address)
+			},
+		})
+	}
+
+	serviceNames, _ := cmd.Flags().GetStringArray("serviceName")
+	if validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic") != nil {
+		if len(serviceNames) > 0 {
+			return nil, errors.New("--serviceName requires the access seed of the keychain")
+		}
+		if len(inputs) == 0 {
+			return nil, errors.New("at least one address, seed or keychain service is required")
+		}
+		return inputs, nil
+	}
+	seed, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+	if err != nil {
+		return nil, err
+	}
+	if len(serviceNames) == 0 {
+		curve, err := ellipticCurve.GetCurve()
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, newDerivedChainInput("seed", func(index uint) (string, error) {
+			address, err := archethic.DeriveAddress(seed, uint32(index), curve, archethic.SHA256)
+			return hex.EncodeToString(address), err
+		}))
+		return inputs, nil
+	}
+
+	client := archethic.NewAPIClient(endpoint.String())
+	keychain, err := archethic.GetKeychain(seed, *client)
+	if err != nil {
+		return nil, err
+	}
+	for _, serviceName := range serviceNames {
+		serviceName := serviceName
+		inputs = append(inputs, newDerivedChainInput("service "+serviceName, func(index uint) (string, error) {
+			address, err := keychain.DeriveAddress(serviceName, uint8(index))
+			return hex.EncodeToString(address), err
+		}))
+	}
+	return inputs, nil
+}
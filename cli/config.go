@@ -0,0 +1,400 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// noProfileAnnotation is the annotation of the commands which don't apply the active profile: the config commands, which manage the profiles
+const noProfileAnnotation = "no-profile"
+
+// profileName is the profile selected by the global --profile flag
+var profileName string
+
+// profileSeedFlags are the flags set from the seed source of the active profile
+var profileSeedFlags []string
+
+// seedFlags are the flags passing the seed of the commands, the seed source of a profile is only used if none of them is set
+var seedFlags = []string{"access-seed", "seed", "ssh", "ssh-path", "mnemonic", tuiutils.KeystoreFlag, tuiutils.SignerFlag}
+
+// profileKeys are the keys of a profile, as written in the configuration file
+var profileKeys = []string{"endpoint", "elliptic_curve", "hash_algorithm", "seed_source", "service_name"}
+
+// SetupGlobalFlags adds the global --output and --profile flags, which are applied before running each command
+func SetupGlobalFlags(rootCmd *cobra.Command) {
+	setupOutputFlag(rootCmd)
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Profile of the configuration file providing the default values of the flags (default to $"+tuiutils.ProfileEnv+" or the current profile)")
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		applyDefaultOutput(cmd)
+		if _, ok := cmd.Annotations[noProfileAnnotation]; !ok {
+			CheckError(applyProfile(cmd, args))
+		}
+	}
+}
+
+// disableProfile marks a command which doesn't apply the active profile
+func disableProfile(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[noProfileAnnotation] = "true"
+}
+
+// applyProfile sets the flags which are not passed to the values of the active profile.
+// The seed source and the keychain service of the profile are only used if no seed and no chain address is passed.
+func applyProfile(cmd *cobra.Command, args []string) error {
+	config, err := tuiutils.LoadConfig()
+	if err != nil {
+		return err
+	}
+	name := config.ActiveProfile(profileName)
+	if name == "" {
+		return nil
+	}
+	profile, ok := config.Profiles[name]
+	if !ok {
+		return fmt.Errorf("the profile %s doesn't exist", name)
+	}
+
+	flags := cmd.Flags()
+	if profile.Endpoint != "" && !flags.Changed("endpoint") {
+		if err := endpoint.Set(profile.Endpoint); err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+	}
+	if profile.EllipticCurve != "" && !flags.Changed("elliptic-curve") {
+		if err := ellipticCurve.Set(profile.EllipticCurve); err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+	}
+	if profile.HashAlgorithm != "" && !flags.Changed("hash-algorithm") {
+		if err := hashAlgo.Set(profile.HashAlgorithm); err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+	}
+
+	if profile.SeedSource == "" || seedFlagsChanged(flags) {
+		return nil
+	}
+	if flags.Lookup("address") != nil && (flags.Changed("address") || len(args) > 0) {
+		return nil
+	}
+	if err := applySeedSource(flags, profile.SeedSource); err != nil {
+		return fmt.Errorf("profile %s: %w", name, err)
+	}
+	if profile.ServiceName != "" && len(profileSeedFlags) > 0 && flags.Lookup("serviceName") != nil && !flags.Changed("serviceName") {
+		if err := flags.Set("serviceName", profile.ServiceName); err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+		profileSeedFlags = append(profileSeedFlags, "serviceName")
+	}
+	return nil
+}
+
+func seedFlagsChanged(flags *pflag.FlagSet) bool {
+	for _, name := range seedFlags {
+		if flags.Lookup(name) != nil && flags.Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// applySeedSource sets the seed flag of the command matching the seed source: ssh, ssh:<path of the key>, mnemonic,
+// keystore:<name of the entry>, signer:<command of the external signer> or an access seed
+func applySeedSource(flags *pflag.FlagSet, seedSource string) error {
+	var name, value string
+	switch {
+	case strings.HasPrefix(seedSource, "keystore:"):
+		name, value = tuiutils.KeystoreFlag, strings.TrimPrefix(seedSource, "keystore:")
+	case strings.HasPrefix(seedSource, "signer:"):
+		name, value = tuiutils.SignerFlag, strings.TrimPrefix(seedSource, "signer:")
+	case seedSource == "ssh":
+		name, value = "ssh", "true"
+	case strings.HasPrefix(seedSource, "ssh:"):
+		name, value = "ssh-path", strings.TrimPrefix(seedSource, "ssh:")
+	case seedSource == "mnemonic":
+		name, value = "mnemonic", "true"
+	case flags.Lookup("access-seed") != nil:
+		name, value = "access-seed", seedSource
+	default:
+		name, value = "seed", seedSource
+	}
+	if flags.Lookup(name) == nil {
+		// the command doesn't use this seed source
+		return nil
+	}
+	if err := flags.Set(name, value); err != nil {
+		return err
+	}
+	profileSeedFlags = append(profileSeedFlags, name)
+	return nil
+}
+
+// resetProfileSeed restores the flags set from the seed source and the keychain service of the profile,
+// when the seed is provided by another way (a configuration file)
+func resetProfileSeed(flags *pflag.FlagSet) {
+	for _, name := range profileSeedFlags {
+		flag := flags.Lookup(name)
+		if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+			sliceValue.Replace(nil)
+		} else {
+			flag.Value.Set(flag.DefValue)
+		}
+		flag.Changed = false
+	}
+	profileSeedFlags = nil
+}
+
+// ProfileInfo is a profile of the configuration file
+type ProfileInfo struct {
+	Name    string `json:"name"`
+	Current bool   `json:"current"`
+	tuiutils.Profile
+}
+
+// ProfileList lists the profiles of the configuration file
+type ProfileList struct {
+	Path     string        `json:"path"`
+	Profiles []ProfileInfo `json:"profiles"`
+}
+
+// ConfigValue is a value of a profile
+type ConfigValue struct {
+	Profile string `json:"profile"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+}
+
+// CurrentProfile is the profile selected by the config use command
+type CurrentProfile struct {
+	CurrentProfile string `json:"current_profile"`
+}
+
+func GetConfigCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the profiles of the configuration file",
+		Long: `Manage the profiles of the configuration file, which hold the default values of the flags of the commands.
+
+The profile is selected by the --profile flag, the ` + tuiutils.ProfileEnv + ` environment variable or the current profile (set by config use).
+The keys of a profile are: ` + strings.Join(profileKeys, ", ") + `.`,
+	}
+
+	configGetCmd := &cobra.Command{
+		Use:   "get [key]",
+		Short: "Display the selected profile, or one of its values",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			config, err := tuiutils.LoadConfig()
+			CheckError(err)
+			name, err := selectedProfile(config)
+			CheckError(err)
+			profile, ok := config.Profiles[name]
+			if !ok {
+				CheckError(fmt.Errorf("the profile %s doesn't exist", name))
+			}
+			profile.SeedSource = describeSeedSource(profile.SeedSource)
+			if len(args) == 0 {
+				printResult(ProfileInfo{Name: name, Current: name == config.CurrentProfile, Profile: profile})
+				return
+			}
+			value, err := getProfileValue(profile, args[0])
+			CheckError(err)
+			printResult(ConfigValue{Profile: name, Key: args[0], Value: value})
+		},
+	}
+
+	configSetCmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a value of the selected profile, which is created if it doesn't exist (an empty value removes the key)",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			config, err := tuiutils.LoadConfig()
+			CheckError(err)
+			name, err := selectedProfile(config)
+			CheckError(err)
+			profile := config.Profiles[name]
+			CheckError(setProfileValue(&profile, args[0], args[1]))
+			config.Profiles[name] = profile
+			if config.CurrentProfile == "" {
+				// the first profile is the current one
+				config.CurrentProfile = name
+			}
+			CheckError(tuiutils.SaveConfig(config))
+			profile.SeedSource = describeSeedSource(profile.SeedSource)
+			value, _ := getProfileValue(profile, args[0])
+			printResult(ConfigValue{Profile: name, Key: args[0], Value: value})
+		},
+	}
+
+	configListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the profiles",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			config, err := tuiutils.LoadConfig()
+			CheckError(err)
+			path, err := tuiutils.ConfigPath()
+			CheckError(err)
+			list := ProfileList{Path: path, Profiles: []ProfileInfo{}}
+			for _, name := range config.ProfileNames() {
+				profile := config.Profiles[name]
+				profile.SeedSource = describeSeedSource(profile.SeedSource)
+				list.Profiles = append(list.Profiles, ProfileInfo{Name: name, Current: name == config.CurrentProfile, Profile: profile})
+			}
+			printResult(list)
+		},
+	}
+
+	configUseCmd := &cobra.Command{
+		Use:   "use <profile>",
+		Short: "Select the current profile",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			config, err := tuiutils.LoadConfig()
+			CheckError(err)
+			if _, ok := config.Profiles[args[0]]; !ok {
+				CheckError(fmt.Errorf("the profile %s doesn't exist", args[0]))
+			}
+			config.CurrentProfile = args[0]
+			CheckError(tuiutils.SaveConfig(config))
+			printResult(CurrentProfile{CurrentProfile: args[0]})
+		},
+	}
+	setDefaultOutput(configListCmd, tableOutput)
+
+	for _, subCmd := range []*cobra.Command{configGetCmd, configSetCmd, configListCmd, configUseCmd} {
+		disableProfile(subCmd)
+		configCmd.AddCommand(subCmd)
+	}
+	return configCmd
+}
+
+// selectedProfile returns the name of the profile selected by the --profile flag, the environment variable or the current profile
+func selectedProfile(config tuiutils.Config) (string, error) {
+	name := config.ActiveProfile(profileName)
+	if name == "" {
+		return "", errors.New("no profile is selected: pass --profile, set " + tuiutils.ProfileEnv + " or select one with config use")
+	}
+	return name, nil
+}
+
+func getProfileValue(profile tuiutils.Profile, key string) (string, error) {
+	switch key {
+	case "endpoint":
+		return profile.Endpoint, nil
+	case "elliptic_curve":
+		return profile.EllipticCurve, nil
+	case "hash_algorithm":
+		return profile.HashAlgorithm, nil
+	case "seed_source":
+		return profile.SeedSource, nil
+	case "service_name":
+		return profile.ServiceName, nil
+	default:
+		return "", fmt.Errorf("invalid key %q: must be one of %s", key, strings.Join(profileKeys, ", "))
+	}
+}
+
+// setProfileValue validates and sets a value of the profile
+func setProfileValue(profile *tuiutils.Profile, key string, value string) error {
+	switch key {
+	case "endpoint":
+		if value != "" {
+			var e EndpointCLI
+			if err := e.Set(value); err != nil || e.String() == "" {
+				return fmt.Errorf("invalid endpoint %q: must be local, testnet, mainnet or a URL", value)
+			}
+		}
+		profile.Endpoint = value
+	case "elliptic_curve":
+		if value != "" {
+			var c CurveCLI
+			if err := c.Set(value); err != nil {
+				return fmt.Errorf("invalid elliptic curve %q: must be ED25519, P256 or SECP256K1", value)
+			}
+		}
+		profile.EllipticCurve = value
+	case "hash_algorithm":
+		if value != "" {
+			var h HashAlgoCLI
+			if err := h.Set(value); err != nil {
+				return fmt.Errorf("invalid hash algorithm %q: must be SHA256, SHA512, SHA3_256, SHA3_512 or BLAKE2B", value)
+			}
+		}
+		profile.HashAlgorithm = value
+	case "seed_source":
+		// the access seeds are only accepted as secret references, to not write them in clear
+		if value != "" && !isSeedSourceReference(value) {
+			return errors.New("invalid seed source: must be ssh, ssh:<path of the key>, mnemonic, keystore:<name of the entry>, signer:<command> or a secret reference to an access seed")
+		}
+		profile.SeedSource = value
+	case "service_name":
+		profile.ServiceName = value
+	default:
+		return fmt.Errorf("invalid key %q: must be one of %s", key, strings.Join(profileKeys, ", "))
+	}
+	return nil
+}
+
+// isSeedSourceReference reports whether the seed source refers to a seed, instead of being an access seed in clear
+func isSeedSourceReference(seedSource string) bool {
+	return seedSource == "ssh" || seedSource == "mnemonic" || strings.HasPrefix(seedSource, "ssh:") ||
+		strings.HasPrefix(seedSource, "keystore:") || strings.HasPrefix(seedSource, "signer:") || tuiutils.IsSecretReference(seedSource)
+}
+
+// describeSeedSource hides the access seeds written in the configuration file, their secret references are shown
+func describeSeedSource(seedSource string) string {
+	if seedSource == "" || isSeedSourceReference(seedSource) {
+		return seedSource
+	}
+	return "access seed"
+}
+
+func (p ProfileInfo) describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Profile: %s", p.Name)
+	if p.Current {
+		b.WriteString(" (current)")
+	}
+	b.WriteString("\n")
+	for _, key := range profileKeys {
+		if value, _ := getProfileValue(p.Profile, key); value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", key, value)
+		}
+	}
+	return b.String()
+}
+
+func (l ProfileList) rows() ([]string, [][]string) {
+	rows := make([][]string, len(l.Profiles))
+	for i, p := range l.Profiles {
+		current := ""
+		if p.Current {
+			current = "*"
+		}
+		rows[i] = []string{current, p.Name, p.Endpoint, p.EllipticCurve, p.HashAlgorithm, p.SeedSource, p.ServiceName}
+	}
+	return []string{"CURRENT", "NAME", "ENDPOINT", "ELLIPTIC CURVE", "HASH ALGORITHM", "SEED SOURCE", "SERVICE"}, rows
+}
+
+func (l ProfileList) describe() string {
+	if len(l.Profiles) == 0 {
+		return fmt.Sprintf("No profile in %s\n", l.Path)
+	}
+	return formatTable(l.rows())
+}
+
+func (v ConfigValue) describe() string {
+	return v.Value + "\n"
+}
+
+func (c CurrentProfile) describe() string {
+	return fmt.Sprintf("Current profile: %s\n", c.CurrentProfile)
+}
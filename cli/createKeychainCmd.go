@@ -1,37 +1,75 @@
 package cli
 
 import (
-	"encoding/json"
+	"encoding/hex"
 	"fmt"
+	"strings"
 
 	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	"github.com/spf13/cobra"
 )
 
+// CreatedKeychain is the result of the create-keychain command, the URLs are the explorer pages of the transactions
+type CreatedKeychain struct {
+	KeychainSeed string `json:"keychain_seed,omitempty"`
+	// KeychainSeedKeystore is the keystore entry of the keychain seed, which is not displayed when it is saved
+	KeychainSeedKeystore         string `json:"keychain_seed_keystore,omitempty"`
+	KeychainTransactionURL       string `json:"keychain_transaction_url"`
+	KeychainAccessTransactionURL string `json:"keychain_access_transaction_url"`
+	feedback                     string
+}
+
 func GetCreateKeychainCmd() *cobra.Command {
 	createKeychainCmd := &cobra.Command{
 		Use:   "create-keychain",
 		Short: "Create keychain",
 		Run: func(cmd *cobra.Command, args []string) {
 			err := validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
-			cobra.CheckErr(err)
-			accessSeedBytes, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
-			cobra.CheckErr(err)
-
-			feedback, keychainSeed, keychainTransactionAddress, keychainAccessTransactionAddress, err := tuiutils.CreateKeychain(endpoint.String(), accessSeedBytes)
-			cobra.CheckErr(err)
-
-			data := map[string]interface{}{
-				"feedback":                         feedback,
-				"keychainSeed":                     keychainSeed,
-				"keychainTransactionAddress":       keychainTransactionAddress,
-				"keychainAccessTransactionAddress": keychainAccessTransactionAddress,
+			CheckError(err)
+			accessSigner, err := tuiutils.GetSigner(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			CheckError(err)
+
+			// the passphrase is asked before creating the keychain, so that its seed is always saved
+			keystoreName, _ := cmd.Flags().GetString("save-keychain-seed")
+			var passphrase []byte
+			if keystoreName != "" {
+				if _, err := tuiutils.GetKeystoreEntry(keystoreName); err == nil {
+					CheckError(fmt.Errorf("the keystore entry %s already exists", keystoreName))
+				}
+				passphrase, err = tuiutils.NewKeystorePassphrase(tuiutils.KeystorePassphraseEnv, keystoreName)
+				CheckError(err)
 			}
 
-			jsonData, err := json.Marshal(data)
-			cobra.CheckErr(err)
+			feedback, keychainSeed, keychainTransactionAddress, keychainAccessTransactionAddress, createErr := tuiutils.CreateKeychain(endpoint.String(), accessSigner)
+			// without keychain seed, the keychain was not created
+			if keychainSeed == "" {
+				checkSendError(createErr)
+			}
 
-			fmt.Println(string(jsonData))
+			createdKeychain := CreatedKeychain{
+				KeychainSeed:                 keychainSeed,
+				KeychainTransactionURL:       keychainTransactionAddress,
+				KeychainAccessTransactionURL: keychainAccessTransactionAddress,
+				feedback:                     feedback,
+			}
+			if keystoreName != "" {
+				keychainSeedBytes, err := hex.DecodeString(keychainSeed)
+				if err == nil {
+					_, err = tuiutils.SaveKeystoreSeed(keystoreName, tuiutils.KeystoreKeychainSeed, keychainSeedBytes, passphrase, tuiutils.KeystoreArgon2id)
+				}
+				if err != nil {
+					// the keychain seed is displayed, it can't be recovered otherwise
+					printResult(createdKeychain)
+					CheckError(fmt.Errorf("the keychain seed was not saved in the keystore: %w", err))
+				}
+				createdKeychain.KeychainSeed = ""
+				createdKeychain.KeychainSeedKeystore = keystoreName
+			}
+			printResult(createdKeychain)
+			// the keychain is created even if its access transaction failed, so the result is printed before the error
+			if createErr != nil {
+				checkSendError(createErr)
+			}
 		},
 	}
 	createKeychainCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url])")
@@ -39,10 +77,29 @@ func GetCreateKeychainCmd() *cobra.Command {
 	createKeychainCmd.Flags().Bool("ssh", false, "Enable SSH key mode")
 	createKeychainCmd.Flags().String("ssh-path", GetFirstSshKeyDefaultPath(), "Path to ssh key")
 	createKeychainCmd.Flags().Bool("mnemonic", false, "Enable mnemonic words for seed")
+	createKeychainCmd.Flags().String("save-keychain-seed", "", "Name of the keystore entry saving the keychain seed, which is not displayed")
 	createKeychainCmd.MarkFlagsMutuallyExclusive("access-seed", "ssh")
 	createKeychainCmd.MarkFlagsMutuallyExclusive("access-seed", "ssh-path")
 	createKeychainCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh")
 	createKeychainCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh-path")
 	createKeychainCmd.MarkFlagsMutuallyExclusive("mnemonic", "access-seed")
+	setupKeystoreFlag(createKeychainCmd)
+	setupMnemonicFlags(createKeychainCmd)
+	setupSignerFlag(createKeychainCmd)
 	return createKeychainCmd
 }
+
+func (k CreatedKeychain) describe() string {
+	var b strings.Builder
+	b.WriteString(strings.TrimPrefix(k.feedback, "\n") + "\n")
+	if k.KeychainSeedKeystore != "" {
+		fmt.Fprintf(&b, "Keychain seed: saved in the keystore entry %s\n", k.KeychainSeedKeystore)
+	} else {
+		fmt.Fprintf(&b, "Keychain seed: %s\n", k.KeychainSeed)
+	}
+	fmt.Fprintf(&b, "Keychain transaction: %s\n", k.KeychainTransactionURL)
+	if k.KeychainAccessTransactionURL != "" {
+		fmt.Fprintf(&b, "Keychain access transaction: %s\n", k.KeychainAccessTransactionURL)
+	}
+	return b.String()
+}
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	fungibleToken    = "fungible"
+	nonFungibleToken = "non-fungible"
+	// maxTokenDecimals is the precision of the amounts on the network
+	maxTokenDecimals = 8
+)
+
+// tokenContent is the content of a token transaction, as defined by the AEIP-2
+type tokenContent struct {
+	Aeip       []int                    `json:"aeip"`
+	Supply     *big.Int                 `json:"supply"`
+	Type       string                   `json:"type"`
+	Decimals   *uint                    `json:"decimals,omitempty"`
+	Name       string                   `json:"name"`
+	Symbol     string                   `json:"symbol"`
+	Properties map[string]interface{}   `json:"properties"`
+	Collection []map[string]interface{} `json:"collection,omitempty"`
+}
+
+// tokenContentResult is the generated content of a token transaction, printed as it is in the text format
+type tokenContentResult []byte
+
+func (c tokenContentResult) MarshalJSON() ([]byte, error) {
+	return c, nil
+}
+
+func (c tokenContentResult) describe() string {
+	return string(c) + "\n"
+}
+
+func GetCreateTokenCmd() *cobra.Command {
+	createTokenCmd := &cobra.Command{
+		Use:   "create-token",
+		Short: "Create a token",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cmd.Flags().Changed("content") || cmd.Flags().Changed("transaction-type") {
+				CheckError(errors.New("--content and --transaction-type can't be used with create-token, the content is generated from the token definition"))
+			}
+
+			token := loadTokenDefinition(cmd)
+			content, err := buildTokenContent(token)
+			CheckError(err)
+
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			if dryRun {
+				printResult(tokenContentResult(content))
+				return
+			}
+
+			configuredTransaction, _ := loadTransactionConfiguration(cmd)
+			configuredTransaction.content = content
+			transaction, secretKey, curve := buildConfiguredTransaction(configuredTransaction, archethic.TokenType)
+			printResult(runTransactionAction(cmd, transaction, secretKey, curve, configuredTransaction, sendTransactionAction(cmd)))
+		},
+	}
+
+	setupTransactionFlags(createTokenCmd)
+	createTokenCmd.Flags().MarkHidden("content")
+	createTokenCmd.Flags().MarkHidden("transaction-type")
+	createTokenCmd.Flags().String("token-name", "", "Token name")
+	createTokenCmd.Flags().String("token-symbol", "", "Token symbol")
+	createTokenCmd.Flags().String("token-supply", "", "Token supply (number of tokens, or number of items for a non-fungible token)")
+	createTokenCmd.Flags().String("token-type", fungibleToken, "Token type (fungible|non-fungible)")
+	createTokenCmd.Flags().Uint("token-decimals", maxTokenDecimals, "Number of decimals of a fungible token (0 to 8)")
+	createTokenCmd.Flags().String("token-properties", "", "Token properties (JSON object)")
+	createTokenCmd.Flags().String("token-collection", "", "The file location of the properties of each item of a non-fungible token (JSON or YAML list of objects)")
+	createTokenCmd.Flags().Bool("dry-run", false, "Print the generated content without sending the transaction")
+	setupFeeCheckFlags(createTokenCmd)
+	setupConfirmationFlags(createTokenCmd, "default to 0, only wait until the transaction is sent")
+	return createTokenCmd
+}
+
+// loadTokenDefinition reads the token section of the configuration file (if any) and the token flags
+func loadTokenDefinition(cmd *cobra.Command) TokenData {
+	var token TokenData
+	config, _ := cmd.Flags().GetString("config")
+	if config != "" {
+		_, data, err := extractTransactionFromInputFile(config)
+		CheckError(err)
+		if data.Token != nil {
+			token = *data.Token
+		}
+	}
+	err := extractTokenFromInputFlags(cmd, &token)
+	CheckError(err)
+	return token
+}
+
+// extractTokenFromInputFlags overrides the token definition of the configuration file by the flags
+func extractTokenFromInputFlags(cmd *cobra.Command, token *TokenData) error {
+	if cmd.Flags().Changed("token-name") {
+		token.Name, _ = cmd.Flags().GetString("token-name")
+	}
+	if cmd.Flags().Changed("token-symbol") {
+		token.Symbol, _ = cmd.Flags().GetString("token-symbol")
+	}
+	if cmd.Flags().Changed("token-supply") {
+		token.Supply, _ = cmd.Flags().GetString("token-supply")
+	}
+	if cmd.Flags().Changed("token-type") || token.Type == "" {
+		token.Type, _ = cmd.Flags().GetString("token-type")
+	}
+	if cmd.Flags().Changed("token-decimals") {
+		decimals, _ := cmd.Flags().GetUint("token-decimals")
+		token.Decimals = &decimals
+	}
+
+	properties, _ := cmd.Flags().GetString("token-properties")
+	if properties != "" {
+		d := json.NewDecoder(strings.NewReader(properties))
+		d.UseNumber()
+		if err := d.Decode(&token.Properties); err != nil {
+			return fmt.Errorf("invalid token properties: %w", err)
+		}
+	}
+
+	collection, _ := cmd.Flags().GetString("token-collection")
+	if collection != "" {
+		collectionBytes, err := os.ReadFile(collection)
+		if err != nil {
+			return err
+		}
+		// YAML is a superset of JSON
+		if err := yaml.Unmarshal(collectionBytes, &token.Collection); err != nil {
+			return fmt.Errorf("invalid token collection: %w", err)
+		}
+	}
+	return nil
+}
+
+// buildTokenContent validates the token definition and returns the content of the token transaction
+func buildTokenContent(token TokenData) ([]byte, error) {
+	if token.Name == "" {
+		return nil, errors.New("the token name is required")
+	}
+	if token.Symbol == "" {
+		return nil, errors.New("the token symbol is required")
+	}
+	if token.Supply == "" {
+		return nil, errors.New("the token supply is required")
+	}
+
+	supply, err := archethic.ParseBigInt(token.Supply, maxTokenDecimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token supply %q: %w", token.Supply, err)
+	}
+	if supply.Sign() <= 0 {
+		return nil, errors.New("the token supply must be greater than 0")
+	}
+	supplyDecimals := countDecimals(token.Supply)
+
+	content := tokenContent{
+		Aeip:       []int{2},
+		Supply:     supply,
+		Type:       token.Type,
+		Name:       token.Name,
+		Symbol:     token.Symbol,
+		Properties: token.Properties,
+		Collection: token.Collection,
+	}
+	if content.Properties == nil {
+		content.Properties = map[string]interface{}{}
+	}
+
+	switch token.Type {
+	case fungibleToken:
+		decimals := uint(maxTokenDecimals)
+		if token.Decimals != nil {
+			decimals = *token.Decimals
+		}
+		if decimals > maxTokenDecimals {
+			return nil, fmt.Errorf("invalid token decimals %d: must be between 0 and %d", decimals, maxTokenDecimals)
+		}
+		if supplyDecimals > decimals {
+			return nil, fmt.Errorf("invalid token supply %q: it has more than %d decimals", token.Supply, decimals)
+		}
+		if len(token.Collection) > 0 {
+			return nil, errors.New("a collection can only be defined for a non-fungible token")
+		}
+		if decimals != maxTokenDecimals {
+			content.Decimals = &decimals
+		}
+	case nonFungibleToken:
+		if token.Decimals != nil && *token.Decimals != 0 {
+			return nil, errors.New("a non-fungible token can't have decimals")
+		}
+		if supplyDecimals > 0 {
+			return nil, fmt.Errorf("invalid token supply %q: the supply of a non-fungible token is a number of items", token.Supply)
+		}
+		nbItems := new(big.Int).Div(supply, big.NewInt(100_000_000))
+		if len(token.Collection) > 0 && nbItems.Cmp(big.NewInt(int64(len(token.Collection)))) != 0 {
+			return nil, fmt.Errorf("the collection has %d items but the supply is %s", len(token.Collection), nbItems.String())
+		}
+	default:
+		return nil, fmt.Errorf("invalid token type %q: must be %s or %s", token.Type, fungibleToken, nonFungibleToken)
+	}
+
+	return json.Marshal(content)
+}
+
+// countDecimals returns the number of significant decimals of a number
+func countDecimals(number string) uint {
+	parts := strings.SplitN(number, ".", 2)
+	if len(parts) < 2 {
+		return 0
+	}
+	return uint(len(strings.TrimRight(parts[1], "0")))
+}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+)
+
+const contentPreviewSize = 256
+
+// DecodedTransaction is a transaction in the format of the transaction files, with the status of its previous signature
+type DecodedTransaction struct {
+	tuiutils.TransactionFile
+	// PreviousSignatureStatus is valid, invalid or not signed
+	PreviousSignatureStatus string `json:"previousSignatureStatus"`
+	transaction             *archethic.TransactionBuilder
+}
+
+// signedTransactionFile is the transaction file printed by sign-transaction, described as by decode-transaction in the text format
+type signedTransactionFile struct {
+	tuiutils.TransactionFile
+	transaction *archethic.TransactionBuilder
+}
+
+func GetDecodeTransactionCmd() *cobra.Command {
+	decodeTransactionCmd := &cobra.Command{
+		Use:   "decode-transaction <file|hex_payload>",
+		Short: "Decode and display a transaction file or a hex encoded transaction payload",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			transaction, err := readTransactionInput(args[0])
+			CheckError(err)
+			decoded := DecodedTransaction{transaction: transaction}
+			decoded.TransactionFile, err = tuiutils.NewTransactionFile(transaction)
+			// the transactions of an unknown type can only be described
+			if err != nil && output.structured() {
+				CheckError(err)
+			}
+			decoded.PreviousSignatureStatus = previousSignatureStatus(transaction)
+			printResult(decoded)
+		},
+	}
+	return decodeTransactionCmd
+}
+
+// readTransactionInput accepts a JSON transaction file (as written by sign-transaction),
+// a file containing a hex encoded payload, or a hex encoded payload directly
+func readTransactionInput(input string) (*archethic.TransactionBuilder, error) {
+	inputBytes, err := os.ReadFile(input)
+	if err != nil {
+		// not a readable file, so the input may be the payload itself
+		inputBytes = []byte(input)
+	}
+	inputBytes = bytes.TrimSpace(inputBytes)
+
+	if bytes.HasPrefix(inputBytes, []byte("{")) {
+		return tuiutils.ParseTransactionFile(inputBytes)
+	}
+
+	payload, err := hex.DecodeString(string(inputBytes))
+	if err != nil {
+		return nil, fmt.Errorf("input is neither a readable transaction file nor a hex encoded payload: %w", err)
+	}
+	return tuiutils.ParseTransactionPayload(payload)
+}
+
+func describeTransaction(transaction *archethic.TransactionBuilder) string {
+	var b strings.Builder
+
+	txType, err := transaction.TxType.String()
+	if err != nil {
+		txType = fmt.Sprintf("unknown (%d)", transaction.TxType)
+	}
+	fmt.Fprintf(&b, "Type: %s\n", txType)
+	fmt.Fprintf(&b, "Version: %d\n", transaction.Version)
+	fmt.Fprintf(&b, "Address: %s\n", formatHex(transaction.Address))
+
+	fmt.Fprintf(&b, "\nUCO transfers (%d):\n", len(transaction.Data.Ledger.Uco.Transfers))
+	for _, t := range transaction.Data.Ledger.Uco.Transfers {
+		fmt.Fprintf(&b, "  - to: %s\n    amount: %s UCO\n", formatHex(t.To), archethic.FormatBigInt(t.Amount, 8))
+	}
+
+	fmt.Fprintf(&b, "\nToken transfers (%d):\n", len(transaction.Data.Ledger.Token.Transfers))
+	for _, t := range transaction.Data.Ledger.Token.Transfers {
+		fmt.Fprintf(&b, "  - to: %s\n    amount: %s\n    token address: %s\n    token id: %d\n", formatHex(t.To), archethic.FormatBigInt(t.Amount, 8), formatHex(t.TokenAddress), t.TokenId)
+	}
+
+	fmt.Fprintf(&b, "\nRecipients (%d):\n", len(transaction.Data.Recipients))
+	for _, r := range transaction.Data.Recipients {
+		fmt.Fprintf(&b, "  - address: %s\n", formatHex(r.Address))
+		if len(r.Action) > 0 || r.Args != nil {
+			argsJson, err := json.Marshal(r.Args)
+			if err != nil {
+				argsJson = []byte(err.Error())
+			}
+			fmt.Fprintf(&b, "    action: %s\n    args: %s\n", r.Action, argsJson)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nOwnerships (%d):\n", len(transaction.Data.Ownerships))
+	for _, o := range transaction.Data.Ownerships {
+		fmt.Fprintf(&b, "  - encrypted secret: %d bytes\n    authorized public keys:\n", len(o.Secret))
+		for _, k := range o.AuthorizedKeys {
+			fmt.Fprintf(&b, "      - %s\n", formatHex(k.PublicKey))
+		}
+	}
+
+	fmt.Fprintf(&b, "\nContent: %d bytes\n", len(transaction.Data.Content))
+	if len(transaction.Data.Content) > 0 {
+		b.WriteString(indent(contentPreview(transaction.Data.Content), "  ") + "\n")
+	}
+
+	fmt.Fprintf(&b, "\nCode: %d bytes\n", len(transaction.Data.Code))
+	if len(transaction.Data.Code) > 0 {
+		b.WriteString(indent(string(transaction.Data.Code), "  ") + "\n")
+	}
+
+	b.WriteString("\n")
+	if len(transaction.PreviousPublicKey) == 0 {
+		b.WriteString("Signature: not signed\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Previous public key: %s\n", formatHex(transaction.PreviousPublicKey))
+	valid, err := tuiutils.VerifyPreviousSignature(transaction)
+	switch {
+	case err != nil:
+		fmt.Fprintf(&b, "Previous signature: invalid (%s)\n", err.Error())
+	case valid:
+		b.WriteString("Previous signature: valid\n")
+	default:
+		b.WriteString("Previous signature: invalid\n")
+	}
+	if len(transaction.OriginSignature) > 0 {
+		b.WriteString("Origin signature: present\n")
+	} else {
+		b.WriteString("Origin signature: missing\n")
+	}
+	return b.String()
+}
+
+func (t DecodedTransaction) describe() string {
+	return describeTransaction(t.transaction)
+}
+
+func (f signedTransactionFile) describe() string {
+	return describeTransaction(f.transaction)
+}
+
+func previousSignatureStatus(transaction *archethic.TransactionBuilder) string {
+	if len(transaction.PreviousPublicKey) == 0 {
+		return "not signed"
+	}
+	if valid, err := tuiutils.VerifyPreviousSignature(transaction); err != nil || !valid {
+		return "invalid"
+	}
+	return "valid"
+}
+
+func formatHex(value []byte) string {
+	return strings.ToUpper(hex.EncodeToString(value))
+}
+
+// contentPreview shows the beginning of the content as text if it is printable, as hex otherwise
+func contentPreview(content []byte) string {
+	truncated := len(content) > contentPreviewSize
+	if truncated {
+		content = content[:contentPreviewSize]
+	}
+	var preview string
+	if utf8.Valid(content) && !bytes.ContainsAny(content, "\x00") {
+		preview = string(content)
+	} else {
+		preview = "(binary) " + hex.EncodeToString(content)
+	}
+	if truncated {
+		preview += "..."
+	}
+	return preview
+}
+
+func indent(text string, prefix string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	return prefix + strings.Join(lines, "\n"+prefix)
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+)
+
+func GetDecryptOwnershipCmd() *cobra.Command {
+	decryptOwnershipCmd := &cobra.Command{
+		Use:   "decrypt-ownership <transaction-address>",
+		Short: "Decrypt the secrets of the ownerships of a transaction shared with your key",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			format, _ := cmd.Flags().GetString("format")
+			if format != "hex" && format != "raw" {
+				CheckError(fmt.Errorf("invalid format %q: must be hex or raw", format))
+			}
+			address := args[0]
+			if _, err := hex.DecodeString(address); err != nil {
+				CheckError(fmt.Errorf("invalid transaction address %q: %w", address, err))
+			}
+
+			err := validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			CheckError(err)
+			signer, err := tuiutils.GetSigner(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			CheckError(err)
+			index, _ := cmd.Flags().GetUint("index")
+			serviceName, _ := cmd.Flags().GetString("serviceName")
+
+			client := archethic.NewAPIClient(endpoint.String())
+			var publicKey []byte
+			var decryptSecretKey func([]byte) ([]byte, error)
+			if serviceName != "" {
+				if index > 255 {
+					CheckError(errors.New("the index of a key of a keychain service must be lower than 256"))
+				}
+				// the keychain is decrypted with the access key, the keys of its services are then derived locally
+				keychain, err := tuiutils.GetKeychain(signer, *client)
+				CheckError(err)
+				var privateKey []byte
+				publicKey, privateKey, err = keychain.DeriveKeypair(serviceName, uint8(index))
+				CheckError(err)
+				decryptSecretKey = func(cipherText []byte) ([]byte, error) {
+					return archethic.EcDecrypt(cipherText, privateKey)
+				}
+			} else {
+				curve, err := ellipticCurve.GetCurve()
+				CheckError(err)
+				publicKey, err = signer.PublicKey(curve, uint32(index))
+				CheckError(err)
+				decryptSecretKey = func(cipherText []byte) ([]byte, error) {
+					return signer.Decrypt(curve, uint32(index), cipherText)
+				}
+			}
+
+			ownerships, err := client.GetTransactionOwnerships(address)
+			CheckError(err)
+			if len(ownerships) == 0 {
+				CheckError(fmt.Errorf("the transaction %s doesn't have any ownership", strings.ToUpper(address)))
+			}
+			decrypted, err := tuiutils.DecryptOwnerships(ownerships, publicKey, decryptSecretKey)
+			CheckError(err)
+			if cmd.Flags().Changed("ownership") {
+				ownershipIndex, _ := cmd.Flags().GetInt("ownership")
+				var selected []tuiutils.DecryptedOwnership
+				for _, d := range decrypted {
+					if d.Index == ownershipIndex {
+						selected = append(selected, d)
+					}
+				}
+				if len(selected) == 0 {
+					CheckError(fmt.Errorf("the ownership %d of the transaction doesn't authorize your public key %s", ownershipIndex, formatHex(publicKey)))
+				}
+				decrypted = selected
+			}
+			if len(decrypted) == 0 {
+				CheckError(fmt.Errorf("no ownership of the transaction authorizes your public key %s", formatHex(publicKey)))
+			}
+
+			outputFile, _ := cmd.Flags().GetString("output-file")
+			if format == "raw" {
+				if output.structured() {
+					CheckError(fmt.Errorf("the raw format can't be printed in %s, use the hex format", output))
+				}
+				if len(decrypted) > 1 {
+					CheckError(fmt.Errorf("%d ownerships authorize your public key, select one with --ownership to get a raw secret", len(decrypted)))
+				}
+				if outputFile != "" {
+					CheckError(os.WriteFile(outputFile, decrypted[0].Secret, 0600))
+				} else {
+					os.Stdout.Write(decrypted[0].Secret)
+				}
+				return
+			}
+
+			result := newDecryptedSecrets(address, publicKey, decrypted)
+			if outputFile != "" {
+				resultBytes, err := formatResult(result)
+				CheckError(err)
+				CheckError(os.WriteFile(outputFile, resultBytes, 0600))
+			} else {
+				printResult(result)
+			}
+		},
+	}
+
+	decryptOwnershipCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url])")
+	decryptOwnershipCmd.Flags().String("access-seed", "", "Access Seed")
+	decryptOwnershipCmd.Flags().Bool("ssh", false, "Enable SSH key mode")
+	decryptOwnershipCmd.Flags().String("ssh-path", GetFirstSshKeyDefaultPath(), "Path to ssh key")
+	decryptOwnershipCmd.Flags().Bool("mnemonic", false, "Enable mnemonic words for seed")
+	decryptOwnershipCmd.MarkFlagsMutuallyExclusive("access-seed", "ssh")
+	decryptOwnershipCmd.MarkFlagsMutuallyExclusive("access-seed", "ssh-path")
+	decryptOwnershipCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh")
+	decryptOwnershipCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh-path")
+	decryptOwnershipCmd.MarkFlagsMutuallyExclusive("mnemonic", "access-seed")
+	setupKeystoreFlag(decryptOwnershipCmd)
+	setupMnemonicFlags(decryptOwnershipCmd)
+	setupSignerFlag(decryptOwnershipCmd)
+	decryptOwnershipCmd.Flags().Var(&ellipticCurve, "elliptic-curve", "Elliptic Curve (ED25519|P256|SECP256K1)")
+	decryptOwnershipCmd.Flags().String("serviceName", "", "Service Name of the keychain of the seed, whose key is authorized")
+	decryptOwnershipCmd.Flags().Uint("index", 0, "Index of the authorized key derived from the seed or the keychain service")
+	decryptOwnershipCmd.Flags().Int("ownership", 0, "Position of the ownership in the transaction, starting at 0 (default: all the ownerships authorizing the key)")
+	decryptOwnershipCmd.Flags().String("format", "hex", "Output format of the secret (hex|raw)")
+	decryptOwnershipCmd.Flags().String("output-file", "", "The file location where the secrets are written, in the output format, instead of the standard output")
+	return decryptOwnershipCmd
+}
+
+type DecryptedSecrets struct {
+	Address   string            `json:"address"`
+	PublicKey string            `json:"publicKey"`
+	Secrets   []DecryptedSecret `json:"secrets"`
+}
+
+type DecryptedSecret struct {
+	Ownership int    `json:"ownership"`
+	Secret    string `json:"secret"`
+}
+
+func newDecryptedSecrets(address string, publicKey []byte, decrypted []tuiutils.DecryptedOwnership) DecryptedSecrets {
+	secrets := make([]DecryptedSecret, len(decrypted))
+	for i, d := range decrypted {
+		secrets[i] = DecryptedSecret{Ownership: d.Index, Secret: hex.EncodeToString(d.Secret)}
+	}
+	return DecryptedSecrets{Address: strings.ToLower(address), PublicKey: hex.EncodeToString(publicKey), Secrets: secrets}
+}
+
+func (s DecryptedSecrets) describe() string {
+	var b strings.Builder
+	for _, secret := range s.Secrets {
+		if len(s.Secrets) > 1 {
+			fmt.Fprintf(&b, "%d: ", secret.Ownership)
+		}
+		b.WriteString(secret.Secret)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
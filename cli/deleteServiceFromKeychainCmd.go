@@ -1,8 +1,6 @@
 package cli
 
 import (
-	"fmt"
-
 	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	"github.com/spf13/cobra"
 )
@@ -14,12 +12,13 @@ func GetDeleteServiceFromKeychainCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			serviceName, _ := cmd.Flags().GetString("service-name")
 			err := validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
-			cobra.CheckErr(err)
-			accessSeedBytes, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
-			cobra.CheckErr(err)
-			feedback, err := tuiutils.RemoveServiceFromKeychain(accessSeedBytes, endpoint.String(), serviceName)
-			cobra.CheckErr(err)
-			fmt.Println(feedback)
+			CheckError(err)
+			accessSigner, err := tuiutils.GetSigner(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			CheckError(err)
+			waitConfirmations, timeout := getConfirmationFlags(cmd, tuiutils.AllConfirmations)
+			feedback, err := tuiutils.RemoveServiceFromKeychain(accessSigner, endpoint.String(), serviceName, waitConfirmations, timeout)
+			checkSendError(err)
+			printResult(newKeychainUpdate(serviceName, "", feedback, waitConfirmations))
 		},
 	}
 	deleteServiceFromKeychainCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url])")
@@ -33,5 +32,9 @@ func GetDeleteServiceFromKeychainCmd() *cobra.Command {
 	deleteServiceFromKeychainCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh")
 	deleteServiceFromKeychainCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh-path")
 	deleteServiceFromKeychainCmd.MarkFlagsMutuallyExclusive("mnemonic", "access-seed")
+	setupKeystoreFlag(deleteServiceFromKeychainCmd)
+	setupMnemonicFlags(deleteServiceFromKeychainCmd)
+	setupSignerFlag(deleteServiceFromKeychainCmd)
+	setupConfirmationFlags(deleteServiceFromKeychainCmd, "default to all confirmations")
 	return deleteServiceFromKeychainCmd
 }
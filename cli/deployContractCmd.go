@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+)
+
+func GetDeployContractCmd() *cobra.Command {
+	deployContractCmd := &cobra.Command{
+		Use:   "deploy-contract",
+		Short: "Deploy a smart contract, with the ownership letting the nodes handle its chain",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cmd.Flags().Changed("transaction-type") {
+				CheckError(errors.New("--transaction-type can't be used with deploy-contract, the transaction is always a contract transaction"))
+			}
+
+			configuredTransaction, _ := loadTransactionConfiguration(cmd)
+			if configuredTransaction.smartContract == "" {
+				CheckError(errors.New("the smart contract is required (--smart-contract or smartContract in the configuration file)"))
+			}
+			transaction, secretKey, curve := buildConfiguredTransaction(configuredTransaction, archethic.ContractType)
+			printResult(runTransactionAction(cmd, transaction, secretKey, curve, configuredTransaction, deployContractAction(cmd)))
+		},
+	}
+
+	setupTransactionFlags(deployContractCmd)
+	deployContractCmd.Flags().MarkHidden("transaction-type")
+	deployContractCmd.Flags().Bool("dry-run", false, "Print the contract ownership and the transaction fee without sending the transaction")
+	setupFeeCheckFlags(deployContractCmd)
+	setupConfirmationFlags(deployContractCmd, "default to 0, only wait until the transaction is sent")
+	return deployContractCmd
+}
+
+// deployContractAction adds the contract ownership to the transaction and prints it before sending the transaction
+func deployContractAction(cmd *cobra.Command) transactionAction {
+	return func(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, index uint, serviceName string, storageNouncePublicKey string, signer tuiutils.Signer) (commandResult, error) {
+		chainSeed, err := tuiutils.GetContractChainSeed(endpoint, signer, serviceMode, serviceName)
+		if err != nil {
+			return nil, err
+		}
+		err = tuiutils.AddContractOwnership(transaction, secretKey, storageNouncePublicKey, chainSeed)
+		if err != nil {
+			return nil, err
+		}
+		printMessage(tuiutils.DescribeContractOwnership(transaction, secretKey, storageNouncePublicKey, chainSeed))
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if dryRun {
+			return getTransactionFeeAction(transaction, secretKey, curve, serviceMode, endpoint, index, serviceName, storageNouncePublicKey, signer)
+		}
+		return sendTransactionAction(cmd)(transaction, secretKey, curve, serviceMode, endpoint, index, serviceName, storageNouncePublicKey, signer)
+	}
+}
@@ -0,0 +1,493 @@
+package cli
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+)
+
+// websiteSigner signs the transactions of the chain of the website, at consecutive indexes
+type websiteSigner struct {
+	signer         tuiutils.Signer
+	curve          archethic.Curve
+	keychain       *archethic.Keychain
+	serviceName    string
+	genesisAddress string
+	nextIndex      uint
+}
+
+// websitePlan lists the changes of an incremental deployment, compared to the hosted website
+type websitePlan struct {
+	added     []string
+	changed   []string
+	removed   []string
+	unchanged []string
+}
+
+// websiteTransaction is a transaction of a website deployment, with the parts of files it holds
+type websiteTransaction struct {
+	transaction *archethic.TransactionBuilder
+	parts       []tuiutils.WebsitePart
+	fee         *big.Int
+}
+
+func GetDeployWebsiteCmd() *cobra.Command {
+	deployWebsiteCmd := &cobra.Command{
+		Use:   "deploy-website <directory>",
+		Short: "Deploy a static website, hosted by the nodes on the chain of a seed or keychain service",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			CheckError(err)
+			accessSigner, err := tuiutils.GetSigner(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			CheckError(err)
+			curve, err := ellipticCurve.GetCurve()
+			CheckError(err)
+			serviceName, _ := cmd.Flags().GetString("serviceName")
+
+			var sslCertificate, sslKey []byte
+			sslCertificatePath, _ := cmd.Flags().GetString("ssl-certificate")
+			sslKeyPath, _ := cmd.Flags().GetString("ssl-key")
+			if sslCertificatePath != "" {
+				sslCertificate, err = os.ReadFile(sslCertificatePath)
+				CheckError(err)
+				sslKey, err = os.ReadFile(sslKeyPath)
+				CheckError(err)
+			}
+
+			files, err := readWebsiteDirectory(args[0])
+			CheckError(err)
+
+			client := archethic.NewAPIClient(endpoint.String())
+			signer, err := newWebsiteSigner(client, accessSigner, curve, serviceName)
+			CheckError(err)
+
+			// in incremental mode, only the new and changed files are uploaded
+			manifest := tuiutils.NewWebsiteManifest()
+			uploads := files
+			incremental, _ := cmd.Flags().GetBool("incremental")
+			var hosted *tuiutils.HostedWebsite
+			var plan websitePlan
+			if incremental {
+				hosted, err = tuiutils.GetHostedWebsite(endpoint.String(), signer.genesisAddress)
+				CheckError(err)
+				plan, uploads = planWebsiteUpdate(files, hosted, manifest)
+				if plan.upToDate() && sslCertificate == nil {
+					printMessage(plan.describe(nil))
+					printMessage("\nThe website is up to date, no transaction to send\n")
+					printResult(newWebsiteDeployment(signer.genesisAddress, &plan, nil, true, 0))
+					return
+				}
+			}
+
+			contents, err := tuiutils.PackWebsiteFiles(uploads)
+			CheckError(err)
+			for _, file := range uploads {
+				manifest.MetaData[file.Path] = tuiutils.WebsiteFileMeta{
+					Encoding:  "gzip",
+					Hash:      file.Hash,
+					Size:      len(file.Data),
+					Addresses: []string{},
+				}
+			}
+
+			var transactions []*websiteTransaction
+			for _, parts := range contents {
+				tx, err := signer.newFileTransaction(parts)
+				CheckError(err)
+				address := strings.ToUpper(hex.EncodeToString(tx.transaction.Address))
+				for _, part := range parts {
+					meta := manifest.MetaData[part.Path]
+					meta.Addresses = append(meta.Addresses, address)
+					manifest.MetaData[part.Path] = meta
+				}
+				transactions = append(transactions, tx)
+			}
+
+			reference, err := signer.newReferenceTransaction(client, manifest, sslCertificate, sslKey, hosted)
+			CheckError(err)
+			transactions = append(transactions, reference)
+
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			if incremental {
+				CheckError(estimateWebsiteFees(client, transactions))
+				printMessage(plan.describe(transactions) + "\n")
+			} else if dryRun {
+				CheckError(estimateWebsiteFees(client, transactions))
+				printMessage(describeWebsiteDeployment(files, transactions))
+			}
+			waitConfirmations, timeout := getConfirmationFlags(cmd, 1)
+			if !dryRun {
+				for i, tx := range transactions {
+					_, err := tuiutils.BroadcastTransaction(tx.transaction, endpoint.String(), waitConfirmations, timeout)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "Transaction %d/%d (%s): not sent\n", i+1, len(transactions), describeWebsiteTransaction(tx))
+						checkSendError(err)
+					}
+					fmt.Fprintf(os.Stderr, "Transaction %d/%d (%s): %s\n", i+1, len(transactions), describeWebsiteTransaction(tx), strings.ToUpper(hex.EncodeToString(tx.transaction.Address)))
+				}
+			}
+
+			var deployedPlan *websitePlan
+			if incremental {
+				deployedPlan = &plan
+			}
+			printResult(newWebsiteDeployment(signer.genesisAddress, deployedPlan, transactions, dryRun, waitConfirmations))
+		},
+	}
+
+	deployWebsiteCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url])")
+	deployWebsiteCmd.Flags().String("access-seed", "", "Access Seed")
+	deployWebsiteCmd.Flags().Bool("ssh", false, "Enable SSH key mode")
+	deployWebsiteCmd.Flags().String("ssh-path", GetFirstSshKeyDefaultPath(), "Path to ssh key")
+	deployWebsiteCmd.Flags().Bool("mnemonic", false, "Enable mnemonic words for seed")
+	deployWebsiteCmd.MarkFlagsMutuallyExclusive("access-seed", "ssh")
+	deployWebsiteCmd.MarkFlagsMutuallyExclusive("access-seed", "ssh-path")
+	deployWebsiteCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh")
+	deployWebsiteCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh-path")
+	deployWebsiteCmd.MarkFlagsMutuallyExclusive("mnemonic", "access-seed")
+	setupKeystoreFlag(deployWebsiteCmd)
+	setupMnemonicFlags(deployWebsiteCmd)
+	setupSignerFlag(deployWebsiteCmd)
+	deployWebsiteCmd.Flags().Var(&ellipticCurve, "elliptic-curve", "Elliptic Curve (ED25519|P256|SECP256K1)")
+	deployWebsiteCmd.Flags().String("serviceName", "", "Service Name of the keychain of the seed, the website is deployed on the chain of this service")
+	deployWebsiteCmd.Flags().String("ssl-certificate", "", "The file location of the SSL certificate (PEM) of the custom domain of the website")
+	deployWebsiteCmd.Flags().String("ssl-key", "", "The file location of the private key (PEM) of the SSL certificate")
+	deployWebsiteCmd.MarkFlagsRequiredTogether("ssl-certificate", "ssl-key")
+	deployWebsiteCmd.Flags().Bool("incremental", false, "Only upload the files which are new or changed since the website currently deployed on the chain")
+	deployWebsiteCmd.Flags().Bool("dry-run", false, "Print the transactions to send and their fee without sending them")
+	setupConfirmationFlags(deployWebsiteCmd, "default to 1, the transactions of the chain are sent in order")
+	return deployWebsiteCmd
+}
+
+// readWebsiteDirectory reads and encodes the files of the directory and its sub directories, ordered by path.
+// The hidden files and directories are ignored.
+func readWebsiteDirectory(dir string) ([]tuiutils.WebsiteFile, error) {
+	var files []tuiutils.WebsiteFile
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		file, err := tuiutils.EncodeWebsiteFile(filepath.ToSlash(relativePath), data)
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no file found in %s", dir)
+	}
+	return files, nil
+}
+
+func newWebsiteSigner(client *archethic.APIClient, accessSigner tuiutils.Signer, curve archethic.Curve, serviceName string) (*websiteSigner, error) {
+	signer := &websiteSigner{signer: accessSigner, curve: curve, serviceName: serviceName}
+	var genesisAddress []byte
+	var err error
+	if serviceName != "" {
+		signer.keychain, err = tuiutils.GetKeychain(accessSigner, *client)
+		if err != nil {
+			return nil, err
+		}
+		genesisAddress, err = signer.keychain.DeriveAddress(serviceName, 0)
+	} else {
+		genesisAddress, err = tuiutils.SignerAddress(accessSigner, 0, curve, archethic.SHA256)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer.genesisAddress = strings.ToUpper(hex.EncodeToString(genesisAddress))
+	signer.nextIndex = client.GetLastTransactionIndex(hex.EncodeToString(genesisAddress))
+	return signer, nil
+}
+
+// sign signs the transaction as the next transaction of the chain
+func (s *websiteSigner) sign(transaction *archethic.TransactionBuilder) error {
+	var err error
+	if s.keychain != nil {
+		if s.nextIndex > 255 {
+			return errors.New("the chain of a keychain service can't hold more than 256 transactions")
+		}
+		err = tuiutils.SignKeychainTransaction(transaction, nil, s.keychain, s.serviceName, s.nextIndex, "")
+	} else {
+		err = tuiutils.SignTransaction(transaction, nil, s.curve, s.nextIndex, "", s.signer)
+	}
+	if err != nil {
+		return err
+	}
+	s.nextIndex++
+	return nil
+}
+
+// newFileTransaction signs a hosting transaction holding parts of files
+func (s *websiteSigner) newFileTransaction(parts []tuiutils.WebsitePart) (*websiteTransaction, error) {
+	content, err := tuiutils.WebsiteContent(parts)
+	if err != nil {
+		return nil, err
+	}
+	transaction := archethic.NewTransaction(archethic.HostingType)
+	transaction.SetContent(content)
+	if err := s.sign(transaction); err != nil {
+		return nil, err
+	}
+	return &websiteTransaction{transaction: transaction, parts: parts}, nil
+}
+
+// newReferenceTransaction signs the hosting transaction holding the manifest of the website,
+// with the ownership of the SSL key if a certificate is given.
+// Otherwise the certificate of the hosted website, if any, is kept with its ownerships.
+func (s *websiteSigner) newReferenceTransaction(client *archethic.APIClient, manifest tuiutils.WebsiteManifest, sslCertificate []byte, sslKey []byte, hosted *tuiutils.HostedWebsite) (*websiteTransaction, error) {
+	manifest.SslCertificate = string(sslCertificate)
+	var ownerships []archethic.Ownership
+	if sslCertificate == nil && hosted != nil && hosted.Manifest.SslCertificate != "" {
+		manifest.SslCertificate = hosted.Manifest.SslCertificate
+		ownerships = hosted.Ownerships
+	}
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > tuiutils.MaxContentSize {
+		return nil, fmt.Errorf("the manifest of the website is too large (%d bytes, the maximum is %d)", len(content), tuiutils.MaxContentSize)
+	}
+	transaction := archethic.NewTransaction(archethic.HostingType)
+	transaction.SetContent(content)
+	for _, ownership := range ownerships {
+		transaction.AddOwnership(ownership.Secret, ownership.AuthorizedKeys)
+	}
+	if len(sslKey) > 0 {
+		storageNouncePublicKey, err := client.GetStorageNoncePublicKey()
+		if err != nil {
+			return nil, err
+		}
+		if err := tuiutils.AddWebsiteSslKey(transaction, sslKey, storageNouncePublicKey); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.sign(transaction); err != nil {
+		return nil, err
+	}
+	return &websiteTransaction{transaction: transaction}, nil
+}
+
+func estimateWebsiteFees(client *archethic.APIClient, transactions []*websiteTransaction) error {
+	for _, tx := range transactions {
+		fee, err := client.GetTransactionFee(tx.transaction)
+		if err != nil {
+			return err
+		}
+		tx.fee = fee.Fee
+	}
+	return nil
+}
+
+func describeWebsiteTransaction(tx *websiteTransaction) string {
+	if tx.parts == nil {
+		return "manifest"
+	}
+	paths := make([]string, len(tx.parts))
+	for i, part := range tx.parts {
+		paths[i] = part.Path
+	}
+	return strings.Join(paths, ", ")
+}
+
+func describeWebsiteDeployment(files []tuiutils.WebsiteFile, transactions []*websiteTransaction) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Files (%d):\n", len(files))
+	for _, file := range files {
+		fmt.Fprintf(&b, "  %s (%d bytes encoded)\n", file.Path, len(file.Data))
+	}
+	b.WriteString("\n")
+	b.WriteString(describeWebsiteTransactions(transactions))
+	return b.String()
+}
+
+// describeWebsiteTransactions lists the transactions of a deployment with their fee and the estimated total fee
+func describeWebsiteTransactions(transactions []*websiteTransaction) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Transactions (%d):\n", len(transactions))
+	total := new(big.Int)
+	for i, tx := range transactions {
+		fmt.Fprintf(&b, "  %d. %s: %s, fee %s UCO\n", i+1, strings.ToUpper(hex.EncodeToString(tx.transaction.Address)), describeWebsiteTransaction(tx), archethic.FormatBigInt(tx.fee, 8))
+		total.Add(total, tx.fee)
+	}
+	fmt.Fprintf(&b, "\nEstimated total fee: %s UCO\n", archethic.FormatBigInt(total, 8))
+	return b.String()
+}
+
+// planWebsiteUpdate compares the files with the hosted website (nil if nothing is deployed yet) and returns the files to upload.
+// The unchanged files keep the transactions of the hosted website: their metadata is copied to the manifest.
+func planWebsiteUpdate(files []tuiutils.WebsiteFile, hosted *tuiutils.HostedWebsite, manifest tuiutils.WebsiteManifest) (websitePlan, []tuiutils.WebsiteFile) {
+	var plan websitePlan
+	var uploads []tuiutils.WebsiteFile
+	hostedFiles := map[string]tuiutils.WebsiteFileMeta{}
+	if hosted != nil {
+		hostedFiles = hosted.Manifest.MetaData
+	}
+
+	paths := make(map[string]bool, len(files))
+	for _, file := range files {
+		paths[file.Path] = true
+		meta, ok := hostedFiles[file.Path]
+		switch {
+		case !ok:
+			plan.added = append(plan.added, file.Path)
+			uploads = append(uploads, file)
+		case meta.Hash != file.Hash || meta.Encoding != "gzip" || len(meta.Addresses) == 0:
+			plan.changed = append(plan.changed, file.Path)
+			uploads = append(uploads, file)
+		default:
+			plan.unchanged = append(plan.unchanged, file.Path)
+			manifest.MetaData[file.Path] = meta
+		}
+	}
+	for path := range hostedFiles {
+		if !paths[path] {
+			plan.removed = append(plan.removed, path)
+		}
+	}
+	sort.Strings(plan.removed)
+	return plan, uploads
+}
+
+// WebsiteDeployment is the result of the deploy-website command
+type WebsiteDeployment struct {
+	Address      string               `json:"address"`
+	URL          string               `json:"url"`
+	DryRun       bool                 `json:"dry_run"`
+	Plan         *WebsitePlan         `json:"plan,omitempty"`
+	Transactions []WebsiteTransaction `json:"transactions"`
+	TotalFee     string               `json:"total_fee,omitempty"`
+}
+
+type WebsitePlan struct {
+	Added     []string `json:"added"`
+	Changed   []string `json:"changed"`
+	Removed   []string `json:"removed"`
+	Unchanged []string `json:"unchanged"`
+}
+
+type WebsiteTransaction struct {
+	Address string   `json:"address"`
+	Files   []string `json:"files"`
+	Fee     string   `json:"fee,omitempty"`
+	// Status is empty in dry run, when the transaction is not sent
+	Status string `json:"status,omitempty"`
+}
+
+func newWebsiteDeployment(address string, plan *websitePlan, transactions []*websiteTransaction, dryRun bool, waitConfirmations uint) WebsiteDeployment {
+	deployment := WebsiteDeployment{
+		Address:      address,
+		URL:          tuiutils.WebsiteURL(endpoint.String(), address),
+		DryRun:       dryRun,
+		Transactions: []WebsiteTransaction{},
+	}
+	if plan != nil {
+		deployment.Plan = &WebsitePlan{
+			Added:     nonNilPaths(plan.added),
+			Changed:   nonNilPaths(plan.changed),
+			Removed:   nonNilPaths(plan.removed),
+			Unchanged: nonNilPaths(plan.unchanged),
+		}
+	}
+	status := ""
+	if !dryRun {
+		status = tuiutils.TransactionConfirmed.String()
+		if waitConfirmations == 0 {
+			status = tuiutils.TransactionSent.String()
+		}
+	}
+	// the fees are only estimated in dry run and in incremental mode
+	var total *big.Int
+	for _, tx := range transactions {
+		files := []string{}
+		for _, part := range tx.parts {
+			files = append(files, part.Path)
+		}
+		websiteTx := WebsiteTransaction{
+			Address: strings.ToUpper(hex.EncodeToString(tx.transaction.Address)),
+			Files:   files,
+			Status:  status,
+		}
+		if tx.fee != nil {
+			websiteTx.Fee = archethic.FormatBigInt(tx.fee, 8)
+			if total == nil {
+				total = new(big.Int)
+			}
+			total.Add(total, tx.fee)
+		}
+		deployment.Transactions = append(deployment.Transactions, websiteTx)
+	}
+	if total != nil {
+		deployment.TotalFee = archethic.FormatBigInt(total, 8)
+	}
+	return deployment
+}
+
+func nonNilPaths(paths []string) []string {
+	if paths == nil {
+		return []string{}
+	}
+	return paths
+}
+
+func (d WebsiteDeployment) describe() string {
+	return fmt.Sprintf("Website address: %s\nWebsite URL: %s\n", d.Address, d.URL)
+}
+
+func (p websitePlan) upToDate() bool {
+	return len(p.added) == 0 && len(p.changed) == 0 && len(p.removed) == 0
+}
+
+// describe describes the plan and the transactions to send, with their estimated fee
+func (p websitePlan) describe(transactions []*websiteTransaction) string {
+	var b strings.Builder
+	describeFiles := func(title string, paths []string) {
+		fmt.Fprintf(&b, "%s (%d):\n", title, len(paths))
+		for _, path := range paths {
+			fmt.Fprintf(&b, "  %s\n", path)
+		}
+	}
+	describeFiles("Added", p.added)
+	describeFiles("Changed", p.changed)
+	describeFiles("Removed", p.removed)
+	describeFiles("Unchanged", p.unchanged)
+	if transactions != nil {
+		b.WriteString("\n")
+		b.WriteString(describeWebsiteTransactions(transactions))
+	}
+	return b.String()
+}
@@ -2,13 +2,19 @@ package cli
 
 import (
 	"encoding/hex"
-	"fmt"
 
 	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	archethic "github.com/archethic-foundation/libgo"
 	"github.com/spf13/cobra"
 )
 
+type GeneratedAddress struct {
+	Address       string `json:"address"`
+	Index         int    `json:"index"`
+	EllipticCurve string `json:"elliptic_curve"`
+	HashAlgorithm string `json:"hash_algorithm"`
+}
+
 func GetGenerateAddressCmd() *cobra.Command {
 	generateAddressCmd := &cobra.Command{
 		Use:   "generate-address",
@@ -16,17 +22,22 @@ func GetGenerateAddressCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			index, _ := cmd.Flags().GetInt("index")
 			err := validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "seed", "")
-			cobra.CheckErr(err)
+			CheckError(err)
 			seedBytes, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "seed", "")
-			cobra.CheckErr(err)
+			CheckError(err)
 
 			curve, err := ellipticCurve.GetCurve()
-			cobra.CheckErr(err)
+			CheckError(err)
 			hashAlgo, err := hashAlgo.GetHashAlgo()
-			cobra.CheckErr(err)
+			CheckError(err)
 			address, err := archethic.DeriveAddress(seedBytes, uint32(index), curve, hashAlgo)
-			cobra.CheckErr(err)
-			fmt.Println(hex.EncodeToString(address))
+			CheckError(err)
+			printResult(GeneratedAddress{
+				Address:       hex.EncodeToString(address),
+				Index:         index,
+				EllipticCurve: tuiutils.GetCurveName(curve),
+				HashAlgorithm: tuiutils.GetHashAlgorithmName(hashAlgo),
+			})
 		},
 	}
 
@@ -35,8 +46,14 @@ func GetGenerateAddressCmd() *cobra.Command {
 	generateAddressCmd.Flags().String("ssh-path", GetFirstSshKeyDefaultPath(), "Path to ssh key")
 	generateAddressCmd.MarkFlagsMutuallyExclusive("seed", "ssh")
 	generateAddressCmd.MarkFlagsMutuallyExclusive("seed", "ssh-path")
+	setupKeystoreFlag(generateAddressCmd)
+	setupMnemonicFlags(generateAddressCmd)
 	generateAddressCmd.Flags().Int("index", 0, "Index")
 	generateAddressCmd.Flags().Var(&hashAlgo, "hash-algorithm", "Hash Algorithm (SHA256|SHA512|SHA3_256|SHA3_512|BLAKE2B)")
 	generateAddressCmd.Flags().Var(&ellipticCurve, "elliptic-curve", "Elliptic Curve (ED25519|P256|SECP256K1)")
 	return generateAddressCmd
 }
+
+func (a GeneratedAddress) describe() string {
+	return a.Address + "\n"
+}
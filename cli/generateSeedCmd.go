@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// The formats of the seed displayed by generate-seed
+const (
+	seedFormatHex      = "hex"
+	seedFormatMnemonic = "mnemonic"
+	seedFormatBoth     = "both"
+)
+
+// mnemonicQuizWords is the number of words asked back by the verification quiz
+const mnemonicQuizWords = 3
+
+// GeneratedSeed is the result of the generate-seed command, the seed and the mnemonic are omitted after a verification
+type GeneratedSeed struct {
+	Seed      string        `json:"seed,omitempty"`
+	Mnemonic  string        `json:"mnemonic,omitempty"`
+	Language  string        `json:"language,omitempty"`
+	Verified  bool          `json:"verified"`
+	Addresses []SeedAddress `json:"addresses"`
+}
+
+// SeedAddress is the genesis address of the chain of a seed on an elliptic curve
+type SeedAddress struct {
+	EllipticCurve string `json:"elliptic_curve"`
+	Address       string `json:"address"`
+}
+
+func GetGenerateSeedCmd() *cobra.Command {
+	generateSeedCmd := &cobra.Command{
+		Use:     "generate-seed",
+		Aliases: []string{"generate-mnemonic"},
+		Short:   "Generate a random seed, as hexadecimal and BIP39 mnemonic",
+		Long: `Generate a random seed, as hexadecimal and BIP39 mnemonic. The seed is the entropy of the mnemonic, so both
+give the same chains. The genesis addresses of the chains of the seed are displayed.
+
+With --verify, the seed is displayed on the terminal until it is written down, then random words of the mnemonic
+are asked back before displaying the genesis addresses. The seed is then not part of the result.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			words, _ := cmd.Flags().GetInt("words")
+			language, _ := cmd.Flags().GetString("language")
+			format, _ := cmd.Flags().GetString("format")
+			verify, _ := cmd.Flags().GetBool("verify")
+			if format != seedFormatHex && format != seedFormatMnemonic && format != seedFormatBoth {
+				CheckError(fmt.Errorf("invalid format %q: must be %s, %s or %s", format, seedFormatHex, seedFormatMnemonic, seedFormatBoth))
+			}
+			if verify && format == seedFormatHex {
+				CheckError(errors.New("the verification asks for words of the mnemonic, it can't be used with the hex format"))
+			}
+			if verify && !terminal.IsTerminal(int(syscall.Stdin)) {
+				CheckError(errors.New("the verification needs a terminal"))
+			}
+
+			seed, err := tuiutils.NewSeed(words)
+			CheckError(err)
+			mnemonic, err := tuiutils.NewMnemonic(seed, language)
+			CheckError(err)
+
+			result := GeneratedSeed{}
+			if format != seedFormatMnemonic {
+				result.Seed = hex.EncodeToString(seed)
+			}
+			if format != seedFormatHex {
+				result.Mnemonic = mnemonic
+				result.Language = strings.ToLower(language)
+			}
+			if verify {
+				fmt.Fprint(os.Stderr, result.describeSeed())
+				CheckError(verifyMnemonic(mnemonic))
+				result = GeneratedSeed{Verified: true}
+			}
+			for _, curve := range []archethic.Curve{archethic.ED25519, archethic.P256, archethic.SECP256K1} {
+				address, err := archethic.DeriveAddress(seed, 0, curve, archethic.SHA256)
+				CheckError(err)
+				result.Addresses = append(result.Addresses, SeedAddress{EllipticCurve: tuiutils.GetCurveName(curve), Address: hex.EncodeToString(address)})
+			}
+			printResult(result)
+		},
+	}
+	generateSeedCmd.Flags().Int("words", 24, "Number of words of the mnemonic (12|15|18|21|24)")
+	generateSeedCmd.Flags().String("language", tuiutils.DefaultMnemonicLanguage, "Language of the mnemonic ("+strings.Join(tuiutils.MnemonicLanguages(), "|")+")")
+	generateSeedCmd.Flags().String("format", seedFormatBoth, "Format of the seed (hex|mnemonic|both)")
+	generateSeedCmd.Flags().Bool("verify", false, "Ask for random words of the mnemonic before displaying the addresses")
+	return generateSeedCmd
+}
+
+// verifyMnemonic hides the mnemonic once it is written down, then asks for random words of it
+func verifyMnemonic(mnemonic string) error {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Fprint(os.Stderr, "\nWrite down the words, then press enter to verify them")
+	if _, err := reader.ReadString('\n'); err != nil {
+		return err
+	}
+	// clear the screen and its scrollback
+	fmt.Fprint(os.Stderr, "\033[H\033[2J\033[3J")
+
+	positions, err := tuiutils.MnemonicQuiz(mnemonic, mnemonicQuizWords)
+	if err != nil {
+		return err
+	}
+	for _, position := range positions {
+		fmt.Fprintf(os.Stderr, "Word #%d: ", position)
+		word, err := terminal.ReadPassword(int(syscall.Stdin))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return err
+		}
+		if !tuiutils.MnemonicWordMatches(mnemonic, position, string(word)) {
+			return fmt.Errorf("the word #%d doesn't match the mnemonic, generate a new seed and write it down again", position)
+		}
+	}
+	fmt.Fprintln(os.Stderr, "The words match the mnemonic.")
+	return nil
+}
+
+// describeSeed displays the seed and the numbered words of the mnemonic
+func (s GeneratedSeed) describeSeed() string {
+	var b strings.Builder
+	if s.Seed != "" {
+		fmt.Fprintf(&b, "Seed: %s\n", s.Seed)
+	}
+	if s.Mnemonic != "" {
+		fmt.Fprintf(&b, "Mnemonic (%s):\n", s.Language)
+		for i, word := range strings.Fields(s.Mnemonic) {
+			fmt.Fprintf(&b, "%4d. %s\n", i+1, word)
+		}
+	}
+	return b.String()
+}
+
+func (s GeneratedSeed) describe() string {
+	var b strings.Builder
+	b.WriteString(s.describeSeed())
+	if s.Verified {
+		b.WriteString("Mnemonic verified\n")
+	}
+	b.WriteString("Genesis addresses:\n")
+	for _, address := range s.Addresses {
+		fmt.Fprintf(&b, "  %s: %s\n", address.EllipticCurve, address.Address)
+	}
+	return b.String()
+}
+
+func (s GeneratedSeed) rows() ([]string, [][]string) {
+	rows := make([][]string, len(s.Addresses))
+	for i, address := range s.Addresses {
+		rows[i] = []string{address.EllipticCurve, address.Address}
+	}
+	return []string{"ELLIPTIC CURVE", "GENESIS ADDRESS"}, rows
+}
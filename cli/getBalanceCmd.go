@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+)
+
+type BalanceReport struct {
+	Balances []ChainBalance `json:"balances"`
+	Total    *Balance       `json:"total,omitempty"`
+}
+
+type ChainBalance struct {
+	Input       string `json:"input"`
+	LastAddress string `json:"last_address"`
+	Balance
+}
+
+type Balance struct {
+	Uco    string         `json:"uco"`
+	Tokens []TokenBalance `json:"tokens"`
+}
+
+type TokenBalance struct {
+	Address string `json:"address"`
+	Symbol  string `json:"symbol"`
+	TokenId uint   `json:"token_id"`
+	Amount  string `json:"amount"`
+}
+
+// balanceSum accumulates the balances of several chains
+type balanceSum struct {
+	uco    *big.Int
+	tokens map[string]*big.Int
+	order  []TokenBalance
+}
+
+func GetGetBalanceCmd() *cobra.Command {
+	getBalanceCmd := &cobra.Command{
+		Use:   "get-balance",
+		Short: "Get the UCO and token balances of addresses, seeds or keychain services",
+		Run: func(cmd *cobra.Command, args []string) {
+			inputs, err := getChainInputs(cmd)
+			CheckError(err)
+
+			client := archethic.NewAPIClient(endpoint.String())
+			tokens := make(map[string]tuiutils.TokenInfo)
+			total := balanceSum{uco: big.NewInt(0), tokens: make(map[string]*big.Int)}
+			report := BalanceReport{}
+			for _, input := range inputs {
+				lastAddress, err := input.resolve()
+				CheckError(err)
+				balance, err := client.GetBalance(lastAddress)
+				CheckError(err)
+
+				chainBalance := ChainBalance{
+					Input:       input.label,
+					LastAddress: strings.ToUpper(lastAddress),
+					Balance:     Balance{Uco: archethic.FormatBigInt(balance.Uco, 8), Tokens: []TokenBalance{}},
+				}
+				total.uco.Add(total.uco, balance.Uco)
+				for _, token := range balance.Token {
+					tokenAddress := strings.ToUpper(hex.EncodeToString(token.Address))
+					info, ok := tokens[tokenAddress]
+					if !ok {
+						info, err = tuiutils.GetTokenInfo(endpoint.String(), tokenAddress)
+						CheckError(err)
+						tokens[tokenAddress] = info
+					}
+					tokenBalance := TokenBalance{
+						Address: tokenAddress,
+						Symbol:  info.Symbol,
+						TokenId: token.TokenId,
+						Amount:  formatTokenAmount(token.Amount, info.Decimals),
+					}
+					chainBalance.Tokens = append(chainBalance.Tokens, tokenBalance)
+					total.add(tokenBalance, token.Amount)
+				}
+				report.Balances = append(report.Balances, chainBalance)
+			}
+
+			if len(inputs) > 1 {
+				report.Total = &Balance{Uco: archethic.FormatBigInt(total.uco, 8), Tokens: []TokenBalance{}}
+				for _, token := range total.order {
+					token.Amount = formatTokenAmount(total.tokens[balanceSumKey(token)], tokens[token.Address].Decimals)
+					report.Total.Tokens = append(report.Total.Tokens, token)
+				}
+			}
+
+			printResult(report)
+		},
+	}
+
+	setupChainInputFlags(getBalanceCmd)
+	setDefaultOutput(getBalanceCmd, tableOutput)
+	return getBalanceCmd
+}
+
+func balanceSumKey(token TokenBalance) string {
+	return fmt.Sprintf("%s/%d", token.Address, token.TokenId)
+}
+
+func (s *balanceSum) add(token TokenBalance, amount *big.Int) {
+	key := balanceSumKey(token)
+	if _, ok := s.tokens[key]; !ok {
+		s.tokens[key] = big.NewInt(0)
+		s.order = append(s.order, token)
+	}
+	s.tokens[key].Add(s.tokens[key], amount)
+}
+
+// formatTokenAmount formats an amount (always stored with 8 decimals) with the decimals of the token
+func formatTokenAmount(amount *big.Int, decimals int) string {
+	formatted := archethic.FormatBigInt(amount, 8)
+	if decimals == 0 {
+		formatted = strings.TrimSuffix(formatted, ".0")
+	}
+	return formatted
+}
+
+func (r BalanceReport) rows() ([]string, [][]string) {
+	var rows [][]string
+	addBalanceRows := func(input string, lastAddress string, balance Balance) {
+		rows = append(rows, []string{input, lastAddress, "UCO", "", balance.Uco})
+		for _, token := range balance.Tokens {
+			rows = append(rows, []string{input, lastAddress, fmt.Sprintf("%s (%s)", token.Symbol, token.Address), strconv.FormatUint(uint64(token.TokenId), 10), token.Amount})
+		}
+	}
+	for _, balance := range r.Balances {
+		addBalanceRows(balance.Input, balance.LastAddress, balance.Balance)
+	}
+	if r.Total != nil {
+		addBalanceRows("total", "", *r.Total)
+	}
+	return []string{"INPUT", "LAST ADDRESS", "ASSET", "TOKEN ID", "AMOUNT"}, rows
+}
+
+func (r BalanceReport) describe() string {
+	return formatTable(r.rows())
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+)
+
+type ChainPage struct {
+	Chain        string             `json:"chain"`
+	Page         uint               `json:"page"`
+	PageSize     uint               `json:"page_size"`
+	HasMore      bool               `json:"has_more"`
+	Transactions []ChainTransaction `json:"transactions"`
+}
+
+type ChainTransaction struct {
+	Index        uint            `json:"index"`
+	Address      string          `json:"address"`
+	Type         string          `json:"type"`
+	Timestamp    string          `json:"timestamp"`
+	Fee          string          `json:"fee"`
+	Status       string          `json:"status"`
+	TransfersIn  []ChainTransfer `json:"transfers_in"`
+	TransfersOut []ChainTransfer `json:"transfers_out"`
+}
+
+type ChainTransfer struct {
+	Address      string `json:"address"`
+	Amount       string `json:"amount"`
+	TokenAddress string `json:"token_address,omitempty"`
+	TokenId      uint   `json:"token_id,omitempty"`
+}
+
+func GetGetChainCmd() *cobra.Command {
+	getChainCmd := &cobra.Command{
+		Use:   "get-chain",
+		Short: "List the transactions of a chain",
+		Run: func(cmd *cobra.Command, args []string) {
+			page, _ := cmd.Flags().GetUint("page")
+			pageSize, _ := cmd.Flags().GetUint("page-size")
+			if page == 0 || pageSize == 0 {
+				CheckError(errors.New("--page and --page-size must be greater than 0"))
+			}
+
+			inputs, err := getChainInputs(cmd)
+			CheckError(err)
+			if len(inputs) != 1 {
+				CheckError(errors.New("get-chain accepts only one address, seed or keychain service"))
+			}
+			lastAddress, err := inputs[0].resolve()
+			CheckError(err)
+
+			transactions, hasMore, err := tuiutils.GetTransactionChain(endpoint.String(), lastAddress, (page-1)*pageSize, pageSize)
+			CheckError(err)
+
+			chainPage := ChainPage{
+				Chain:        inputs[0].label,
+				Page:         page,
+				PageSize:     pageSize,
+				HasMore:      hasMore,
+				Transactions: []ChainTransaction{},
+			}
+			for _, tx := range transactions {
+				chainPage.Transactions = append(chainPage.Transactions, newChainTransaction(tx))
+			}
+
+			printResult(chainPage)
+		},
+	}
+
+	setupChainInputFlags(getChainCmd)
+	getChainCmd.Flags().Uint("page", 1, "Page of transactions, starting at 1 with the oldest transactions")
+	getChainCmd.Flags().Uint("page-size", 10, "Number of transactions per page")
+	return getChainCmd
+}
+
+func newChainTransaction(tx tuiutils.ChainTransaction) ChainTransaction {
+	chainTransaction := ChainTransaction{
+		Index:        tx.Index,
+		Address:      tx.Address,
+		Type:         tx.Type,
+		Fee:          archethic.FormatBigInt(tx.Fee, 8),
+		Status:       tx.ValidationStatus(),
+		TransfersIn:  []ChainTransfer{},
+		TransfersOut: []ChainTransfer{},
+	}
+	if tx.Validated {
+		chainTransaction.Timestamp = tx.Timestamp.Format(time.RFC3339)
+	}
+	for _, transfer := range tx.TransfersIn {
+		chainTransaction.TransfersIn = append(chainTransaction.TransfersIn, newChainTransfer(transfer))
+	}
+	for _, transfer := range tx.TransfersOut {
+		chainTransaction.TransfersOut = append(chainTransaction.TransfersOut, newChainTransfer(transfer))
+	}
+	return chainTransaction
+}
+
+func newChainTransfer(transfer tuiutils.ChainTransfer) ChainTransfer {
+	return ChainTransfer{
+		Address:      transfer.Address,
+		Amount:       archethic.FormatBigInt(transfer.Amount, 8),
+		TokenAddress: transfer.TokenAddress,
+		TokenId:      transfer.TokenId,
+	}
+}
+
+func (page ChainPage) rows() ([]string, [][]string) {
+	rows := make([][]string, len(page.Transactions))
+	for i, tx := range page.Transactions {
+		rows[i] = []string{strconv.FormatUint(uint64(tx.Index), 10), tx.Address, tx.Type, tx.Timestamp, tx.Status, tx.Fee}
+	}
+	return []string{"INDEX", "ADDRESS", "TYPE", "TIMESTAMP", "STATUS", "FEE"}, rows
+}
+
+func (page ChainPage) describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Chain of %s, page %d:\n", page.Chain, page.Page)
+	if len(page.Transactions) == 0 {
+		b.WriteString("\nNo transaction\n")
+	}
+	for _, tx := range page.Transactions {
+		fmt.Fprintf(&b, "\n#%d %s %s\n", tx.Index, tx.Type, tx.Address)
+		fmt.Fprintf(&b, "  timestamp: %s\n", tx.Timestamp)
+		fmt.Fprintf(&b, "  status: %s\n", tx.Status)
+		fmt.Fprintf(&b, "  fee: %s UCO\n", tx.Fee)
+		for _, transfer := range tx.TransfersIn {
+			fmt.Fprintf(&b, "  in: %s from %s\n", describeChainTransfer(transfer), transfer.Address)
+		}
+		for _, transfer := range tx.TransfersOut {
+			fmt.Fprintf(&b, "  out: %s to %s\n", describeChainTransfer(transfer), transfer.Address)
+		}
+	}
+	if page.HasMore {
+		fmt.Fprintf(&b, "\nMore transactions: use --page %d\n", page.Page+1)
+	}
+	return b.String()
+}
+
+func describeChainTransfer(transfer ChainTransfer) string {
+	if transfer.TokenAddress == "" {
+		return transfer.Amount + " UCO"
+	}
+	return fmt.Sprintf("%s token %s#%d", transfer.Amount, transfer.TokenAddress, transfer.TokenId)
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+)
+
+type ContractInfo struct {
+	Address         string                 `json:"address"`
+	ChainLength     uint                   `json:"chain_length"`
+	Type            string                 `json:"type"`
+	Timestamp       string                 `json:"timestamp"`
+	Ownerships      []ContractOwnership    `json:"ownerships"`
+	State           map[string]interface{} `json:"state"`
+	Version         string                 `json:"version,omitempty"`
+	Triggers        []ContractTrigger      `json:"triggers"`
+	Conditions      []ContractTrigger      `json:"conditions"`
+	Actions         []ContractFunction     `json:"actions"`
+	PublicFunctions []ContractFunction     `json:"public_functions"`
+	Code            string                 `json:"code"`
+}
+
+type ContractOwnership struct {
+	AuthorizedPublicKeys []string `json:"authorized_public_keys"`
+}
+
+type ContractTrigger struct {
+	Type     string `json:"type"`
+	Argument string `json:"argument,omitempty"`
+}
+
+type ContractFunction struct {
+	Name       string   `json:"name"`
+	Parameters []string `json:"parameters"`
+}
+
+func GetGetContractCmd() *cobra.Command {
+	getContractCmd := &cobra.Command{
+		Use:   "get-contract <address>",
+		Short: "Display the smart contract of a chain: code, ownerships, state and triggers",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			address := args[0]
+			if _, err := hex.DecodeString(address); err != nil {
+				CheckError(fmt.Errorf("invalid contract address %q: %w", address, err))
+			}
+
+			contract, err := tuiutils.GetDeployedContract(endpoint.String(), address)
+			CheckError(err)
+			if contract.Code == "" {
+				CheckError(fmt.Errorf("the last transaction of the chain (%s) doesn't have a smart contract", contract.Address))
+			}
+			printResult(newContractInfo(contract))
+		},
+	}
+
+	getContractCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url])")
+	return getContractCmd
+}
+
+func newContractInfo(contract tuiutils.DeployedContract) ContractInfo {
+	summary := tuiutils.ParseContract(contract.Code)
+	info := ContractInfo{
+		Address:         contract.Address,
+		ChainLength:     contract.ChainLength,
+		Type:            contract.Type,
+		Ownerships:      []ContractOwnership{},
+		State:           contract.State,
+		Version:         summary.Version,
+		Triggers:        []ContractTrigger{},
+		Conditions:      []ContractTrigger{},
+		Actions:         []ContractFunction{},
+		PublicFunctions: []ContractFunction{},
+		Code:            contract.Code,
+	}
+	if !contract.Timestamp.IsZero() {
+		info.Timestamp = contract.Timestamp.Format(time.RFC3339)
+	}
+	for _, publicKeys := range contract.Ownerships {
+		info.Ownerships = append(info.Ownerships, ContractOwnership{AuthorizedPublicKeys: publicKeys})
+	}
+	for _, trigger := range summary.Triggers {
+		info.Triggers = append(info.Triggers, ContractTrigger(trigger))
+	}
+	for _, condition := range summary.Conditions {
+		info.Conditions = append(info.Conditions, ContractTrigger(condition))
+	}
+	for _, action := range summary.Actions {
+		info.Actions = append(info.Actions, ContractFunction(action))
+	}
+	for _, function := range summary.PublicFunctions {
+		info.PublicFunctions = append(info.PublicFunctions, ContractFunction(function))
+	}
+	return info
+}
+
+func (info ContractInfo) describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Contract at %s (%s transaction, chain length %d)\n", info.Address, info.Type, info.ChainLength)
+	if info.Timestamp != "" {
+		fmt.Fprintf(&b, "  timestamp: %s\n", info.Timestamp)
+	}
+	if info.Version != "" {
+		fmt.Fprintf(&b, "  version: %s\n", info.Version)
+	}
+
+	b.WriteString("\nOwnerships:\n")
+	if len(info.Ownerships) == 0 {
+		b.WriteString("  none\n")
+	}
+	for i, ownership := range info.Ownerships {
+		fmt.Fprintf(&b, "  #%d\n", i+1)
+		for _, publicKey := range ownership.AuthorizedPublicKeys {
+			fmt.Fprintf(&b, "    authorized key: %s\n", publicKey)
+		}
+	}
+
+	b.WriteString("\nState:\n")
+	if info.State == nil {
+		b.WriteString("  not provided by the node\n")
+	} else if len(info.State) == 0 {
+		b.WriteString("  empty\n")
+	} else {
+		keys := make([]string, 0, len(info.State))
+		for key := range info.State {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			valueBytes, _ := json.Marshal(info.State[key])
+			fmt.Fprintf(&b, "  %s: %s\n", key, valueBytes)
+		}
+	}
+
+	b.WriteString("\nTriggers:\n")
+	describeContractTriggers(&b, info.Triggers)
+	b.WriteString("\nConditions:\n")
+	describeContractTriggers(&b, info.Conditions)
+	b.WriteString("\nActions:\n")
+	describeContractFunctions(&b, info.Actions)
+	b.WriteString("\nPublic functions:\n")
+	describeContractFunctions(&b, info.PublicFunctions)
+
+	b.WriteString("\nCode:\n")
+	b.WriteString(info.Code)
+	if !strings.HasSuffix(info.Code, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func describeContractTriggers(b *strings.Builder, triggers []ContractTrigger) {
+	if len(triggers) == 0 {
+		b.WriteString("  none\n")
+	}
+	for _, trigger := range triggers {
+		if trigger.Argument == "" {
+			fmt.Fprintf(b, "  %s\n", trigger.Type)
+		} else {
+			fmt.Fprintf(b, "  %s: %s\n", trigger.Type, trigger.Argument)
+		}
+	}
+}
+
+func describeContractFunctions(b *strings.Builder, functions []ContractFunction) {
+	if len(functions) == 0 {
+		b.WriteString("  none\n")
+	}
+	for _, function := range functions {
+		fmt.Fprintf(b, "  %s(%s)\n", function.Name, strings.Join(function.Parameters, ", "))
+	}
+}
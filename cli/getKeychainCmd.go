@@ -1,27 +1,40 @@
 package cli
 
 import (
-	"encoding/json"
+	"encoding/hex"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
 	"github.com/spf13/cobra"
 )
 
+type KeychainInfo struct {
+	Services             []KeychainService `json:"services"`
+	AuthorizedPublicKeys []string          `json:"authorized_public_keys"`
+}
+
+type KeychainService struct {
+	Name           string `json:"name"`
+	DerivationPath string `json:"derivation_path"`
+	EllipticCurve  string `json:"elliptic_curve"`
+	HashAlgorithm  string `json:"hash_algorithm"`
+}
+
 func GetKeychainCmd() *cobra.Command {
 	getKeychainCmd := &cobra.Command{
 		Use:   "get-keychain",
 		Short: "Get keychain",
 		Run: func(cmd *cobra.Command, args []string) {
 			err := validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
-			cobra.CheckErr(err)
-			accessSeedBytes, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
-			cobra.CheckErr(err)
-			keychain, err := tuiutils.AccessKeychain(endpoint.String(), accessSeedBytes)
-			cobra.CheckErr(err)
-			jsonServices, err := json.Marshal(keychain.Services)
-			cobra.CheckErr(err)
-			fmt.Printf("%s\n", jsonServices)
+			CheckError(err)
+			accessSigner, err := tuiutils.GetSigner(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			CheckError(err)
+			keychain, err := tuiutils.AccessKeychain(endpoint.String(), accessSigner)
+			CheckError(err)
+			printResult(newKeychainInfo(keychain))
 		},
 	}
 	getKeychainCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url])")
@@ -34,5 +47,49 @@ func GetKeychainCmd() *cobra.Command {
 	getKeychainCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh")
 	getKeychainCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh-path")
 	getKeychainCmd.MarkFlagsMutuallyExclusive("mnemonic", "access-seed")
+	setupKeystoreFlag(getKeychainCmd)
+	setupMnemonicFlags(getKeychainCmd)
+	setupSignerFlag(getKeychainCmd)
 	return getKeychainCmd
 }
+
+// newKeychainInfo lists the services of the keychain, ordered by name
+func newKeychainInfo(keychain *archethic.Keychain) KeychainInfo {
+	info := KeychainInfo{Services: []KeychainService{}, AuthorizedPublicKeys: []string{}}
+	for name, service := range keychain.Services {
+		info.Services = append(info.Services, KeychainService{
+			Name:           name,
+			DerivationPath: service.DerivationPath,
+			EllipticCurve:  tuiutils.GetCurveName(service.Curve),
+			HashAlgorithm:  tuiutils.GetHashAlgorithmName(service.HashAlgo),
+		})
+	}
+	sort.Slice(info.Services, func(i, j int) bool {
+		return info.Services[i].Name < info.Services[j].Name
+	})
+	for _, publicKey := range keychain.AuthorizedPublicKeys {
+		info.AuthorizedPublicKeys = append(info.AuthorizedPublicKeys, strings.ToUpper(hex.EncodeToString(publicKey)))
+	}
+	return info
+}
+
+func (k KeychainInfo) rows() ([]string, [][]string) {
+	rows := make([][]string, len(k.Services))
+	for i, service := range k.Services {
+		rows[i] = []string{service.Name, service.DerivationPath, service.EllipticCurve, service.HashAlgorithm}
+	}
+	return []string{"SERVICE", "DERIVATION PATH", "CURVE", "HASH ALGORITHM"}, rows
+}
+
+func (k KeychainInfo) describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Services (%d):\n", len(k.Services))
+	for _, service := range k.Services {
+		fmt.Fprintf(&b, "  %s: %s (%s, %s)\n", service.Name, service.DerivationPath, service.EllipticCurve, service.HashAlgorithm)
+	}
+	fmt.Fprintf(&b, "Authorized public keys (%d):\n", len(k.AuthorizedPublicKeys))
+	for _, publicKey := range k.AuthorizedPublicKeys {
+		fmt.Fprintf(&b, "  %s\n", publicKey)
+	}
+	return b.String()
+}
@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// KeystoreResult is the result of the keystore commands changing an entry
+type KeystoreResult struct {
+	tuiutils.KeystoreEntry
+	// Address is the genesis address of the chain of the seed, for the created and imported seeds
+	Address string `json:"address,omitempty"`
+	action  string
+}
+
+// KeystoreList lists the entries of the keystore
+type KeystoreList struct {
+	Path    string                   `json:"path"`
+	Entries []tuiutils.KeystoreEntry `json:"entries"`
+}
+
+// ExportedSeed is a seed decrypted from the keystore
+type ExportedSeed struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Seed string `json:"seed"`
+}
+
+func GetKeystoreCmd() *cobra.Command {
+	keystoreCmd := &cobra.Command{
+		Use:   "keystore",
+		Short: "Manage the seeds of the keystore, encrypted with a passphrase",
+		Long: `Manage the seeds of the keystore, encrypted with a passphrase.
+
+The seeds are encrypted with AES-256-GCM, with a key derived from the passphrase by argon2id or scrypt. Each entry is a file
+of the keystore directory, next to the configuration file. The commands use a seed of the keystore with --keystore <name>.
+
+The passphrase is prompted, or read from the ` + tuiutils.KeystorePassphraseEnv + ` environment variable when the standard
+input is not a terminal (the new passphrase of change-passphrase from ` + tuiutils.KeystoreNewPassphraseEnv + `).
+The value of these variables can be a secret reference, such as file:/run/secrets/passphrase.`,
+	}
+
+	keystoreCreateCmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Generate a random seed and add it to the keystore",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			seed := make([]byte, 32)
+			_, err := rand.Read(seed)
+			CheckError(err)
+			printResult(saveKeystoreSeed(cmd, args[0], tuiutils.KeystoreSeed, seed, "created"))
+		},
+	}
+	keystoreCreateCmd.Flags().String("kdf", tuiutils.KeystoreArgon2id, "Key derivation function of the passphrase (argon2id|scrypt)")
+	keystoreCreateCmd.Flags().Var(&ellipticCurve, "elliptic-curve", "Elliptic Curve of the displayed address (ED25519|P256|SECP256K1)")
+
+	keystoreImportCmd := &cobra.Command{
+		Use:   "import <name>",
+		Short: "Add an existing seed to the keystore",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var seed []byte
+			var err error
+			if validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "seed", "mnemonic") == nil {
+				seed, err = tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "seed", "mnemonic")
+			} else {
+				// read the seed from the standard input, or prompt for it
+				var mnemonicOptions tuiutils.MnemonicOptions
+				mnemonicOptions, err = tuiutils.GetMnemonicOptions(cmd.Flags())
+				if err == nil {
+					seed, err = tuiutils.ParseSeed("-", mnemonicOptions)
+				}
+			}
+			CheckError(err)
+			if len(seed) == 0 {
+				CheckError(fmt.Errorf("the seed is empty"))
+			}
+			kind := tuiutils.KeystoreSeed
+			if keychain, _ := cmd.Flags().GetBool("keychain"); keychain {
+				kind = tuiutils.KeystoreKeychainSeed
+			}
+			printResult(saveKeystoreSeed(cmd, args[0], kind, seed, "imported"))
+		},
+	}
+	keystoreImportCmd.Flags().String("seed", "", "Seed, or a secret reference (the seed is prompted, or read from the standard input, if no seed flag is set)")
+	keystoreImportCmd.Flags().Bool("ssh", false, "Enable SSH key mode")
+	keystoreImportCmd.Flags().String("ssh-path", GetFirstSshKeyDefaultPath(), "Path to ssh key")
+	keystoreImportCmd.Flags().Bool("mnemonic", false, "Enable mnemonic words for seed")
+	keystoreImportCmd.MarkFlagsMutuallyExclusive("seed", "ssh")
+	keystoreImportCmd.MarkFlagsMutuallyExclusive("seed", "ssh-path")
+	keystoreImportCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh")
+	keystoreImportCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh-path")
+	keystoreImportCmd.MarkFlagsMutuallyExclusive("mnemonic", "seed")
+	setupMnemonicFlags(keystoreImportCmd)
+	keystoreImportCmd.Flags().Bool("keychain", false, "The seed is the seed of a keychain")
+	keystoreImportCmd.Flags().String("kdf", tuiutils.KeystoreArgon2id, "Key derivation function of the passphrase (argon2id|scrypt)")
+	keystoreImportCmd.Flags().Var(&ellipticCurve, "elliptic-curve", "Elliptic Curve of the displayed address (ED25519|P256|SECP256K1)")
+
+	keystoreListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the entries of the keystore",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			dir, err := tuiutils.KeystoreDir()
+			CheckError(err)
+			entries, err := tuiutils.ListKeystore()
+			CheckError(err)
+			printResult(KeystoreList{Path: dir, Entries: entries})
+		},
+	}
+	setDefaultOutput(keystoreListCmd, tableOutput)
+
+	keystoreExportCmd := &cobra.Command{
+		Use:   "export <name>",
+		Short: "Decrypt a seed of the keystore and print it in hexadecimal",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			entry, err := tuiutils.GetKeystoreEntry(args[0])
+			CheckError(err)
+			seed, err := tuiutils.GetKeystoreSeed(args[0])
+			CheckError(err)
+			printResult(ExportedSeed{Name: args[0], Kind: entry.Kind, Seed: hex.EncodeToString(seed)})
+		},
+	}
+
+	keystoreRemoveCmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a seed from the keystore",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			_, err := tuiutils.GetKeystoreEntry(args[0])
+			CheckError(err)
+			if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+				CheckError(confirmRemoval(args[0]))
+			}
+			entry, err := tuiutils.RemoveKeystoreEntry(args[0])
+			CheckError(err)
+			printResult(KeystoreResult{KeystoreEntry: entry, action: "removed"})
+		},
+	}
+	keystoreRemoveCmd.Flags().Bool("yes", false, "Don't ask for a confirmation")
+
+	keystoreChangePassphraseCmd := &cobra.Command{
+		Use:   "change-passphrase <name>",
+		Short: "Encrypt a seed of the keystore with a new passphrase",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			kdf, _ := cmd.Flags().GetString("kdf")
+			if kdf != "" {
+				CheckError(validateKDF(kdf))
+			}
+			_, err := tuiutils.GetKeystoreEntry(args[0])
+			CheckError(err)
+			passphrase, err := tuiutils.KeystorePassphrase(args[0])
+			CheckError(err)
+			// check the passphrase before asking for the new one
+			_, err = tuiutils.LoadKeystoreSeed(args[0], passphrase)
+			CheckError(err)
+			newPassphrase, err := tuiutils.NewKeystorePassphrase(tuiutils.KeystoreNewPassphraseEnv, args[0])
+			CheckError(err)
+			entry, err := tuiutils.ChangeKeystorePassphrase(args[0], passphrase, newPassphrase, kdf)
+			CheckError(err)
+			printResult(KeystoreResult{KeystoreEntry: entry, action: "encrypted with the new passphrase"})
+		},
+	}
+	keystoreChangePassphraseCmd.Flags().String("kdf", "", "Key derivation function of the new passphrase (argon2id|scrypt), default to the one of the entry")
+
+	for _, subCmd := range []*cobra.Command{keystoreCreateCmd, keystoreImportCmd, keystoreListCmd, keystoreExportCmd, keystoreRemoveCmd, keystoreChangePassphraseCmd} {
+		// the seed of a profile is not imported
+		disableProfile(subCmd)
+		keystoreCmd.AddCommand(subCmd)
+	}
+	return keystoreCmd
+}
+
+// saveKeystoreSeed encrypts the seed with a new passphrase and adds it to the keystore
+func saveKeystoreSeed(cmd *cobra.Command, name string, kind string, seed []byte, action string) KeystoreResult {
+	kdf, _ := cmd.Flags().GetString("kdf")
+	CheckError(validateKDF(kdf))
+	if _, err := tuiutils.GetKeystoreEntry(name); err == nil {
+		CheckError(fmt.Errorf("the keystore entry %s already exists", name))
+	}
+	passphrase, err := tuiutils.NewKeystorePassphrase(tuiutils.KeystorePassphraseEnv, name)
+	CheckError(err)
+	entry, err := tuiutils.SaveKeystoreSeed(name, kind, seed, passphrase, kdf)
+	CheckError(err)
+	result := KeystoreResult{KeystoreEntry: entry, action: action}
+	if kind == tuiutils.KeystoreSeed {
+		curve, err := ellipticCurve.GetCurve()
+		CheckError(err)
+		address, err := archethic.DeriveAddress(seed, 0, curve, archethic.SHA256)
+		CheckError(err)
+		result.Address = hex.EncodeToString(address)
+	}
+	return result
+}
+
+func validateKDF(kdf string) error {
+	if kdf != tuiutils.KeystoreArgon2id && kdf != tuiutils.KeystoreScrypt {
+		return fmt.Errorf("invalid key derivation function %q: must be %s or %s", kdf, tuiutils.KeystoreArgon2id, tuiutils.KeystoreScrypt)
+	}
+	return nil
+}
+
+// confirmRemoval asks for a confirmation on the terminal before removing a keystore entry
+func confirmRemoval(name string) error {
+	if !terminal.IsTerminal(int(syscall.Stdin)) {
+		return fmt.Errorf("can't ask for a confirmation, the standard input is not a terminal: pass --yes to remove the keystore entry %s", name)
+	}
+	fmt.Fprintf(os.Stderr, "Remove the keystore entry %s? Its seed can't be recovered without a backup [y/N] ", name)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "y" && answer != "yes" {
+		return fmt.Errorf("the keystore entry %s was not removed", name)
+	}
+	return nil
+}
+
+func (r KeystoreResult) describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Keystore entry %s %s\n", r.Name, r.action)
+	if r.Address != "" {
+		fmt.Fprintf(&b, "Genesis address: %s\n", r.Address)
+	}
+	fmt.Fprintf(&b, "Path: %s\n", r.Path)
+	return b.String()
+}
+
+func (l KeystoreList) rows() ([]string, [][]string) {
+	rows := make([][]string, len(l.Entries))
+	for i, entry := range l.Entries {
+		rows[i] = []string{entry.Name, entry.Kind, entry.KDF, entry.CreatedAt.Local().Format(time.DateTime)}
+	}
+	return []string{"NAME", "KIND", "KDF", "CREATED"}, rows
+}
+
+func (l KeystoreList) describe() string {
+	if len(l.Entries) == 0 {
+		return fmt.Sprintf("No entry in the keystore %s\n", l.Path)
+	}
+	return formatTable(l.rows())
+}
+
+func (s ExportedSeed) describe() string {
+	return s.Seed + "\n"
+}
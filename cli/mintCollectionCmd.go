@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// mediaProperty is the item property referencing the media file of an item
+const mediaProperty = "media"
+
+// MintedCollection is the result of the mint-collection command: the token and the token ID of each item
+type MintedCollection struct {
+	// Content is the content of the token transaction, only set by --preview
+	Content      tokenContentResult `json:"content,omitempty"`
+	Fee          *TransactionFee    `json:"fee,omitempty"`
+	Transaction  *SentTransaction   `json:"transaction,omitempty"`
+	TokenAddress string             `json:"token_address"`
+	Items        []MintedItem       `json:"items"`
+}
+
+type MintedItem struct {
+	TokenId      int    `json:"token_id"`
+	MetadataFile string `json:"metadata_file"`
+	MediaFile    string `json:"media_file,omitempty"`
+}
+
+// collectionItem is an item of a non-fungible token, read from a metadata file
+type collectionItem struct {
+	metadataFile string
+	mediaFile    string
+	properties   map[string]interface{}
+}
+
+func GetMintCollectionCmd() *cobra.Command {
+	mintCollectionCmd := &cobra.Command{
+		Use:   "mint-collection <directory>",
+		Short: "Mint a non-fungible token collection from a directory of metadata files",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if cmd.Flags().Changed("content") || cmd.Flags().Changed("transaction-type") {
+				CheckError(errors.New("--content and --transaction-type can't be used with mint-collection, the content is generated from the metadata files"))
+			}
+
+			items, err := readCollectionDirectory(args[0])
+			CheckError(err)
+
+			token := loadTokenDefinition(cmd)
+			token.Type = nonFungibleToken
+			token.Decimals = nil
+			token.Supply = strconv.Itoa(len(items))
+			token.Collection = make([]map[string]interface{}, len(items))
+			for i, item := range items {
+				token.Collection[i] = item.properties
+			}
+			content, err := buildTokenContent(token)
+			CheckError(err)
+
+			configuredTransaction, _ := loadTransactionConfiguration(cmd)
+			configuredTransaction.content = content
+			transaction, secretKey, curve := buildConfiguredTransaction(configuredTransaction, archethic.TokenType)
+
+			minted := MintedCollection{
+				Items: make([]MintedItem, len(items)),
+			}
+			for i, item := range items {
+				minted.Items[i] = MintedItem{TokenId: i + 1, MetadataFile: item.metadataFile, MediaFile: item.mediaFile}
+			}
+
+			preview, _ := cmd.Flags().GetBool("preview")
+			if preview {
+				minted.Content = tokenContentResult(content)
+				fee := runTransactionAction(cmd, transaction, secretKey, curve, configuredTransaction, getTransactionFeeAction).(TransactionFee)
+				minted.Fee = &fee
+			} else {
+				sent := runTransactionAction(cmd, transaction, secretKey, curve, configuredTransaction, sendTransactionAction(cmd)).(SentTransaction)
+				minted.Transaction = &sent
+			}
+			// the address is set when the transaction is built, by the action
+			minted.TokenAddress = strings.ToUpper(hex.EncodeToString(transaction.Address))
+			printResult(minted)
+		},
+	}
+
+	setupTransactionFlags(mintCollectionCmd)
+	mintCollectionCmd.Flags().MarkHidden("content")
+	mintCollectionCmd.Flags().MarkHidden("transaction-type")
+	mintCollectionCmd.Flags().String("token-name", "", "Token name")
+	mintCollectionCmd.Flags().String("token-symbol", "", "Token symbol")
+	mintCollectionCmd.Flags().String("token-properties", "", "Token properties (JSON object)")
+	mintCollectionCmd.Flags().Bool("preview", false, "Print the generated content and the fee without sending the transaction")
+	setupFeeCheckFlags(mintCollectionCmd)
+	setupConfirmationFlags(mintCollectionCmd, "default to 0, only wait until the transaction is sent")
+	return mintCollectionCmd
+}
+
+// readCollectionDirectory reads the metadata files (.json, .yaml, .yml) of the directory, ordered by name.
+// The other files are media files: a media file with the same base name as a metadata file is hashed and
+// referenced in the properties of the item.
+func readCollectionDirectory(dir string) ([]collectionItem, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []collectionItem
+	mediaFiles := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		name := entry.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		baseName := strings.TrimSuffix(name, filepath.Ext(name))
+		switch ext {
+		case ".json", ".yaml", ".yml":
+			for _, item := range items {
+				if strings.TrimSuffix(item.metadataFile, filepath.Ext(item.metadataFile)) == baseName {
+					return nil, fmt.Errorf("several metadata files for the item %s: %s and %s", baseName, item.metadataFile, name)
+				}
+			}
+			items = append(items, collectionItem{metadataFile: name})
+		default:
+			if other, ok := mediaFiles[baseName]; ok {
+				return nil, fmt.Errorf("several media files for the item %s: %s and %s", baseName, other, name)
+			}
+			mediaFiles[baseName] = name
+		}
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no metadata file found in %s", dir)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return lessFileName(items[i].metadataFile, items[j].metadataFile)
+	})
+
+	for i := range items {
+		item := &items[i]
+		metadataBytes, err := os.ReadFile(filepath.Join(dir, item.metadataFile))
+		if err != nil {
+			return nil, err
+		}
+		// YAML is a superset of JSON
+		if err := yaml.Unmarshal(metadataBytes, &item.properties); err != nil {
+			return nil, fmt.Errorf("%s: invalid metadata: %w", item.metadataFile, err)
+		}
+		if item.properties == nil {
+			item.properties = map[string]interface{}{}
+		}
+
+		baseName := strings.TrimSuffix(item.metadataFile, filepath.Ext(item.metadataFile))
+		mediaFile, ok := mediaFiles[baseName]
+		if !ok {
+			continue
+		}
+		if _, ok := item.properties[mediaProperty]; ok {
+			return nil, fmt.Errorf("%s: the %q property is already defined, it can't reference the media file %s", item.metadataFile, mediaProperty, mediaFile)
+		}
+		mediaBytes, err := os.ReadFile(filepath.Join(dir, mediaFile))
+		if err != nil {
+			return nil, err
+		}
+		hash := sha256.Sum256(mediaBytes)
+		item.mediaFile = mediaFile
+		item.properties[mediaProperty] = map[string]interface{}{
+			"name":      mediaFile,
+			"size":      len(mediaBytes),
+			"hash":      hex.EncodeToString(hash[:]),
+			"hash_type": "sha256",
+		}
+	}
+	return items, nil
+}
+
+// lessFileName orders the numbered file names (1.json, 2.json, 10.json) by number, the other ones alphabetically
+func lessFileName(a string, b string) bool {
+	numberA, errA := strconv.Atoi(strings.TrimSuffix(a, filepath.Ext(a)))
+	numberB, errB := strconv.Atoi(strings.TrimSuffix(b, filepath.Ext(b)))
+	switch {
+	case errA == nil && errB == nil:
+		return numberA < numberB
+	case errA == nil:
+		return true
+	case errB == nil:
+		return false
+	default:
+		return a < b
+	}
+}
+
+func (c MintedCollection) describe() string {
+	var b strings.Builder
+	if c.Content != nil {
+		b.WriteString(c.Content.describe())
+	}
+	if c.Fee != nil {
+		b.WriteString(c.Fee.describe())
+	}
+	if c.Transaction != nil {
+		b.WriteString(c.Transaction.describe())
+	}
+	fmt.Fprintf(&b, "\nToken address: %s\n", c.TokenAddress)
+	b.WriteString("Token IDs:\n")
+	for _, item := range c.Items {
+		if item.MediaFile != "" {
+			fmt.Fprintf(&b, "  %d: %s (%s)\n", item.TokenId, item.MetadataFile, item.MediaFile)
+		} else {
+			fmt.Fprintf(&b, "  %d: %s\n", item.TokenId, item.MetadataFile)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// outputAnnotation is the annotation of a command holding its default output format
+const outputAnnotation = "output"
+
+// output is the format of the results and errors printed by the commands, set by the global --output flag
+// or to the default format of the command
+var output OutputCLI
+
+type OutputCLI string
+
+const (
+	textOutput  OutputCLI = "text"
+	tableOutput OutputCLI = "table"
+	jsonOutput  OutputCLI = "json"
+	yamlOutput  OutputCLI = "yaml"
+)
+
+func (o *OutputCLI) String() string {
+	return string(*o)
+}
+
+func (o *OutputCLI) Set(value string) error {
+	switch value {
+	case "text", "table", "json", "yaml":
+		*o = OutputCLI(value)
+	default:
+		return errors.New("invalid Output value")
+	}
+	return nil
+}
+
+func (o *OutputCLI) Type() string {
+	return "OutputCLI"
+}
+
+// structured returns true for the formats meant to be parsed by scripts
+func (o OutputCLI) structured() bool {
+	return o == jsonOutput || o == yamlOutput
+}
+
+// commandResult is the result of a command. It is encoded with its json tags in the json and yaml formats,
+// and described for humans in the text format.
+type commandResult interface {
+	describe() string
+}
+
+// tableResult is a result displayed as a table in the table format, the other results are described as in the text format
+type tableResult interface {
+	commandResult
+	rows() ([]string, [][]string)
+}
+
+// ErrorReport is printed instead of the result when a command fails
+type ErrorReport struct {
+	Error CommandError `json:"error"`
+}
+
+type CommandError struct {
+	Message string `json:"message"`
+	// Code is the stable code of the known errors (insufficient_funds, invalid_index, invalid_signature...)
+	Code tuiutils.ErrorCode `json:"code,omitempty"`
+	// Status is the status of a transaction which was not confirmed (rejected, timeout, unconfirmed or transport error)
+	Status string `json:"status,omitempty"`
+	Hint   string `json:"hint,omitempty"`
+	// NodeErrors are the errors returned by the node, with their context, code, message and data
+	NodeErrors []tuiutils.NodeError `json:"node_errors,omitempty"`
+}
+
+func newCommandError(err error) CommandError {
+	commandError := CommandError{Message: err.Error(), NodeErrors: tuiutils.NodeErrors(err)}
+	var codedError tuiutils.CodedError
+	if errors.As(err, &codedError) {
+		commandError.Code = codedError.ErrorCode()
+		commandError.Hint = codedError.Hint()
+	}
+	return commandError
+}
+
+// setupOutputFlag adds the global --output flag, which defaults to the output format of each command
+func setupOutputFlag(rootCmd *cobra.Command) {
+	rootCmd.PersistentFlags().Var(&output, "output", "Output format (json|yaml|table|text), the default depends on the command (text for most of them)")
+}
+
+// applyDefaultOutput sets the output format of the command, if the --output flag is not set
+func applyDefaultOutput(cmd *cobra.Command) {
+	if !cmd.Flags().Changed("output") {
+		output = defaultOutput(cmd)
+	}
+}
+
+// setDefaultOutput sets the output format of the command when the --output flag is not set
+func setDefaultOutput(cmd *cobra.Command, format OutputCLI) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[outputAnnotation] = string(format)
+}
+
+func defaultOutput(cmd *cobra.Command) OutputCLI {
+	if format, ok := cmd.Annotations[outputAnnotation]; ok {
+		return OutputCLI(format)
+	}
+	return textOutput
+}
+
+// printResult prints the result of the command in the output format
+func printResult(result commandResult) {
+	resultBytes, err := formatResult(result)
+	CheckError(err)
+	os.Stdout.Write(resultBytes)
+}
+
+func formatResult(result commandResult) ([]byte, error) {
+	switch output {
+	case jsonOutput:
+		resultBytes, err := json.MarshalIndent(result, "", "  ")
+		return append(resultBytes, '\n'), err
+	case yamlOutput:
+		return marshalYaml(result)
+	case tableOutput:
+		if table, ok := result.(tableResult); ok {
+			return []byte(formatTable(table.rows())), nil
+		}
+	}
+	return []byte(result.describe()), nil
+}
+
+// printEvent prints a result of a command printing several results as they come:
+// one JSON object per line in json, one document per result in yaml
+func printEvent(result commandResult) {
+	switch output {
+	case jsonOutput:
+		resultBytes, err := json.Marshal(result)
+		CheckError(err)
+		fmt.Println(string(resultBytes))
+	case yamlOutput:
+		resultBytes, err := marshalYaml(result)
+		CheckError(err)
+		fmt.Print("---\n" + string(resultBytes))
+	default:
+		fmt.Print(result.describe())
+	}
+}
+
+// printMessage prints a message about the progress of a command: on the standard output in the text and table formats,
+// on the standard error in the json and yaml formats to keep the standard output parsable
+func printMessage(message string) {
+	if output.structured() {
+		fmt.Fprint(os.Stderr, message)
+	} else {
+		fmt.Print(message)
+	}
+}
+
+// marshalYaml encodes the value with the keys of its json tags, so the json and yaml formats have the same schema
+func marshalYaml(value interface{}) ([]byte, error) {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	// YAML is a superset of JSON: the node keeps the order of the keys and the numbers as they are written
+	var node yaml.Node
+	if err := yaml.Unmarshal(jsonBytes, &node); err != nil {
+		return nil, err
+	}
+	resetYamlStyle(&node)
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	err = encoder.Close()
+	return b.Bytes(), err
+}
+
+// resetYamlStyle replaces the JSON flow style of the nodes by the default block style
+func resetYamlStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYamlStyle(child)
+	}
+}
+
+func formatTable(header []string, rows [][]string) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+	return b.String()
+}
+
+// CheckError prints the error in the output format and exits, if the error is not nil
+func CheckError(err error) {
+	if err == nil {
+		return
+	}
+	printError(newCommandError(err), err)
+	os.Exit(1)
+}
+
+// printError prints an error: on the standard output in the json and yaml formats (so the output of the command
+// can always be parsed), on the standard error otherwise
+func printError(commandError CommandError, err error) {
+	if output.structured() {
+		errorBytes, err := formatResult(ErrorReport{Error: commandError})
+		if err == nil {
+			os.Stdout.Write(errorBytes)
+			return
+		}
+	}
+	fmt.Fprintln(os.Stderr, "Error:", tuiutils.DescribeError(err))
+}
+
+func (r ErrorReport) describe() string {
+	return "Error: " + r.Error.Message + "\n"
+}
@@ -0,0 +1,380 @@
+package cli
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+type SendBatchData struct {
+	Endpoint      string                `yaml:"endpoint"`
+	EllipticCurve string                `yaml:"elliptic_curve"`
+	Transactions  []SendTransactionData `yaml:"transactions"`
+}
+
+type BatchReport struct {
+	Entries []BatchEntryResult `yaml:"entries" json:"entries"`
+}
+
+type BatchEntryResult struct {
+	Entry   int    `yaml:"entry" json:"entry"`
+	Chain   string `yaml:"chain,omitempty" json:"chain,omitempty"`
+	Index   uint   `yaml:"index" json:"index"`
+	Address string `yaml:"address,omitempty" json:"address,omitempty"`
+	Fee     string `yaml:"fee,omitempty" json:"fee,omitempty"`
+	Status  string `yaml:"status" json:"status"`
+	Error   string `yaml:"error,omitempty" json:"error,omitempty"`
+	// Code is the stable code of a known error
+	Code tuiutils.ErrorCode `yaml:"code,omitempty" json:"code,omitempty"`
+}
+
+const (
+	batchStatusFailed  = "failed"
+	batchStatusSkipped = "skipped"
+)
+
+// isDone returns true if the entry doesn't have to be sent again
+func (r BatchEntryResult) isDone() bool {
+	return r.Status == tuiutils.TransactionConfirmed.String() || r.Status == tuiutils.TransactionSent.String()
+}
+
+// batchChain holds the next index of a chain, computed once from the network and then incremented locally
+type batchChain struct {
+	nextIndex uint
+	failed    bool
+}
+
+type batchSender struct {
+	client            *archethic.APIClient
+	endpoint          string
+	waitConfirmations uint
+	timeout           uint
+	chains            map[string]*batchChain
+	keychains         map[string]*archethic.Keychain
+	storageNonce      string
+}
+
+func GetSendBatchCmd() *cobra.Command {
+	sendBatchCmd := &cobra.Command{
+		Use:   "send-batch <file>",
+		Short: "Send a batch of transactions described in a YAML file",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			batchBytes, err := os.ReadFile(args[0])
+			CheckError(err)
+			var batch SendBatchData
+			err = yaml.Unmarshal(batchBytes, &batch)
+			CheckError(err)
+			if len(batch.Transactions) == 0 {
+				CheckError(errors.New("the batch file doesn't contain any transaction"))
+			}
+			if batch.Endpoint != "" && !cmd.Flags().Changed("endpoint") {
+				endpoint.Set(batch.Endpoint)
+			}
+			if batch.EllipticCurve != "" && !cmd.Flags().Changed("elliptic-curve") {
+				CheckError(ellipticCurve.Set(batch.EllipticCurve))
+			}
+
+			// the seed or the external signer passed by flags is used for the entries without access_seed
+			var defaultSigner tuiutils.Signer
+			if validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic") == nil {
+				defaultSigner, err = tuiutils.GetSigner(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+				CheckError(err)
+			}
+
+			reportPath, _ := cmd.Flags().GetString("report")
+			resume, _ := cmd.Flags().GetBool("resume")
+			var previousReport BatchReport
+			if resume {
+				if reportPath == "" {
+					CheckError(errors.New("--resume requires --report"))
+				}
+				previousReport, err = readBatchReport(reportPath)
+				CheckError(err)
+			}
+
+			waitConfirmations, timeout := getConfirmationFlags(cmd, 1)
+			sender := &batchSender{
+				client:            archethic.NewAPIClient(endpoint.String()),
+				endpoint:          endpoint.String(),
+				waitConfirmations: waitConfirmations,
+				timeout:           timeout,
+				chains:            make(map[string]*batchChain),
+				keychains:         make(map[string]*archethic.Keychain),
+			}
+
+			report := BatchReport{}
+			nbNotDone := 0
+			for i, data := range batch.Transactions {
+				var previous *BatchEntryResult
+				if i < len(previousReport.Entries) && previousReport.Entries[i].Entry == i+1 {
+					previous = &previousReport.Entries[i]
+				}
+				result := sender.processEntry(i+1, data, defaultSigner, previous)
+				fmt.Fprintf(os.Stderr, "Transaction %d/%d: %s\n", i+1, len(batch.Transactions), result.Status)
+				if !result.isDone() {
+					nbNotDone++
+				}
+				report.Entries = append(report.Entries, result)
+				if reportPath != "" {
+					CheckError(writeBatchReport(report, reportPath))
+				}
+			}
+
+			if reportPath != "" {
+				if nbNotDone > 0 {
+					CheckError(fmt.Errorf("%d of %d transactions were not sent", nbNotDone, len(batch.Transactions)))
+				}
+				return
+			}
+			printResult(report)
+			if nbNotDone > 0 {
+				// the report is the output of the command, the failure is only reported by the exit code
+				fmt.Fprintf(os.Stderr, "Error: %d of %d transactions were not sent\n", nbNotDone, len(batch.Transactions))
+				os.Exit(1)
+			}
+		},
+	}
+
+	sendBatchCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url])")
+	sendBatchCmd.Flags().String("access-seed", "", "Access Seed, used for the transactions without access_seed")
+	sendBatchCmd.Flags().Bool("ssh", false, "Enable SSH key mode")
+	sendBatchCmd.Flags().String("ssh-path", GetFirstSshKeyDefaultPath(), "Path to ssh key")
+	sendBatchCmd.Flags().Bool("mnemonic", false, "Enable mnemonic words for seed")
+	sendBatchCmd.MarkFlagsMutuallyExclusive("access-seed", "ssh")
+	sendBatchCmd.MarkFlagsMutuallyExclusive("access-seed", "ssh-path")
+	sendBatchCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh")
+	sendBatchCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh-path")
+	sendBatchCmd.MarkFlagsMutuallyExclusive("mnemonic", "access-seed")
+	setupKeystoreFlag(sendBatchCmd)
+	setupMnemonicFlags(sendBatchCmd)
+	setupSignerFlag(sendBatchCmd)
+	sendBatchCmd.Flags().Var(&ellipticCurve, "elliptic-curve", "Elliptic Curve (ED25519|P256|SECP256K1)")
+	sendBatchCmd.Flags().String("report", "", "The file location of the YAML report, written after each transaction (printed on the standard output if not set)")
+	sendBatchCmd.Flags().Bool("resume", false, "Resume a batch from its report, the transactions already sent are skipped")
+	setupConfirmationFlags(sendBatchCmd, "default to 1")
+	setDefaultOutput(sendBatchCmd, yamlOutput)
+	return sendBatchCmd
+}
+
+// processEntry builds, signs and sends one transaction of the batch.
+// If a previous run of the batch already sent it, it is not sent again.
+func (s *batchSender) processEntry(entry int, data SendTransactionData, defaultSigner tuiutils.Signer, previous *BatchEntryResult) BatchEntryResult {
+	result := BatchEntryResult{Entry: entry}
+	fail := func(chain *batchChain, err error) BatchEntryResult {
+		if chain != nil {
+			chain.failed = true
+		}
+		result.Status = batchStatusFailed
+		result.Error = err.Error()
+		var codedError tuiutils.CodedError
+		if errors.As(err, &codedError) {
+			result.Code = codedError.ErrorCode()
+		}
+		return result
+	}
+
+	configuredTransaction, err := configuredTransactionFromData(data)
+	if err != nil {
+		return fail(nil, err)
+	}
+	signer := defaultSigner
+	if len(configuredTransaction.accessSeed) > 0 {
+		signer = tuiutils.SeedSigner(configuredTransaction.accessSeed)
+	}
+	if signer == nil {
+		return fail(nil, checkAccessSeed(nil))
+	}
+
+	curveCLI := ellipticCurve
+	if data.EllipticCurve != "" {
+		if err := curveCLI.Set(data.EllipticCurve); err != nil {
+			return fail(nil, err)
+		}
+	}
+	curve, err := curveCLI.GetCurve()
+	if err != nil {
+		return fail(nil, err)
+	}
+
+	txTypeCLI := TransferType
+	if data.TransactionType != "" {
+		if err := txTypeCLI.Set(data.TransactionType); err != nil {
+			return fail(nil, err)
+		}
+	}
+	txType, err := txTypeCLI.GetTransactionType()
+	if err != nil {
+		return fail(nil, err)
+	}
+
+	// the chain is identified by its genesis address
+	var keychain *archethic.Keychain
+	var genesisAddress []byte
+	if configuredTransaction.serviceName != "" {
+		keychain, err = s.getKeychain(signer)
+		if err != nil {
+			return fail(nil, err)
+		}
+		genesisAddress, err = keychain.DeriveAddress(configuredTransaction.serviceName, 0)
+	} else {
+		genesisAddress, err = tuiutils.SignerAddress(signer, 0, curve, archethic.SHA256)
+	}
+	if err != nil {
+		return fail(nil, err)
+	}
+	result.Chain = strings.ToUpper(hex.EncodeToString(genesisAddress))
+
+	chain, ok := s.chains[result.Chain]
+	if !ok {
+		chain = &batchChain{nextIndex: s.client.GetLastTransactionIndex(hex.EncodeToString(genesisAddress))}
+		s.chains[result.Chain] = chain
+	}
+
+	// a transaction of a previous run is done if its index is already part of the chain
+	if previous != nil && previous.Chain == result.Chain && (previous.isDone() || (previous.Address != "" && previous.Index < chain.nextIndex)) {
+		result = *previous
+		if !result.isDone() {
+			result.Status = tuiutils.TransactionConfirmed.String()
+			result.Error = ""
+			result.Code = ""
+		}
+		return result
+	}
+
+	if chain.failed {
+		result.Status = batchStatusSkipped
+		result.Error = "a previous transaction of the chain was not sent"
+		return result
+	}
+	result.Index = chain.nextIndex
+
+	secretKey := make([]byte, 32)
+	rand.Read(secretKey)
+	transaction, err := configureTransaction(configuredTransaction, txType, secretKey)
+	if err != nil {
+		return fail(chain, err)
+	}
+
+	storageNonce := ""
+	if len(transaction.Data.Code) > 0 {
+		storageNonce, err = s.getStorageNonce()
+		if err != nil {
+			return fail(chain, err)
+		}
+	}
+
+	if keychain != nil {
+		err = tuiutils.SignKeychainTransaction(transaction, secretKey, keychain, configuredTransaction.serviceName, result.Index, storageNonce)
+	} else {
+		err = tuiutils.SignTransaction(transaction, secretKey, curve, result.Index, storageNonce, signer)
+	}
+	if err != nil {
+		return fail(chain, err)
+	}
+	result.Address = strings.ToUpper(hex.EncodeToString(transaction.Address))
+
+	fee, err := s.client.GetTransactionFee(transaction)
+	if err != nil {
+		return fail(chain, err)
+	}
+	result.Fee = archethic.FormatBigInt(fee.Fee, 8)
+
+	_, err = tuiutils.BroadcastTransaction(transaction, s.endpoint, s.waitConfirmations, s.timeout)
+	if err != nil {
+		result = fail(chain, err)
+		var sendErr tuiutils.SendTransactionError
+		if errors.As(err, &sendErr) {
+			result.Status = sendErr.Status.String()
+		}
+		return result
+	}
+
+	chain.nextIndex++
+	if s.waitConfirmations == 0 {
+		result.Status = tuiutils.TransactionSent.String()
+	} else {
+		result.Status = tuiutils.TransactionConfirmed.String()
+	}
+	return result
+}
+
+func (r BatchReport) rows() ([]string, [][]string) {
+	rows := make([][]string, len(r.Entries))
+	for i, entry := range r.Entries {
+		index := ""
+		if entry.Address != "" {
+			index = strconv.FormatUint(uint64(entry.Index), 10)
+		}
+		rows[i] = []string{strconv.Itoa(entry.Entry), entry.Chain, index, entry.Address, entry.Fee, entry.Status, entry.Error}
+	}
+	return []string{"ENTRY", "CHAIN", "INDEX", "ADDRESS", "FEE", "STATUS", "ERROR"}, rows
+}
+
+func (r BatchReport) describe() string {
+	return formatTable(r.rows())
+}
+
+// getKeychain fetches the keychain of the access signer once, the keychains are identified
+// by the access seed or by the command of the external signer
+func (s *batchSender) getKeychain(accessSigner tuiutils.Signer) (*archethic.Keychain, error) {
+	var key string
+	switch accessSigner := accessSigner.(type) {
+	case tuiutils.SeedSigner:
+		key = hex.EncodeToString(accessSigner)
+	case tuiutils.ExternalSigner:
+		key = accessSigner.Command
+	}
+	if keychain, ok := s.keychains[key]; ok {
+		return keychain, nil
+	}
+	keychain, err := tuiutils.GetKeychain(accessSigner, *s.client)
+	if err != nil {
+		return nil, err
+	}
+	s.keychains[key] = keychain
+	return keychain, nil
+}
+
+func (s *batchSender) getStorageNonce() (string, error) {
+	if s.storageNonce != "" {
+		return s.storageNonce, nil
+	}
+	storageNonce, err := s.client.GetStorageNoncePublicKey()
+	if err != nil {
+		return "", err
+	}
+	s.storageNonce = storageNonce
+	return storageNonce, nil
+}
+
+func readBatchReport(path string) (BatchReport, error) {
+	var report BatchReport
+	reportBytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// nothing to resume
+		return report, nil
+	}
+	if err != nil {
+		return report, err
+	}
+	err = yaml.Unmarshal(reportBytes, &report)
+	return report, err
+}
+
+func writeBatchReport(report BatchReport, path string) error {
+	reportBytes, err := yaml.Marshal(report)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, reportBytes, 0644)
+}
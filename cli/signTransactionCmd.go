@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+)
+
+// WrittenTransaction is the result of sign-transaction when the transaction is written to a file
+type WrittenTransaction struct {
+	Address string `json:"address"`
+	File    string `json:"file"`
+}
+
+func GetSignTransactionCmd() *cobra.Command {
+	signTransactionCmd := &cobra.Command{
+		Use:   "sign-transaction",
+		Short: "Sign a transaction offline and write it to a file",
+		Run: func(cmd *cobra.Command, args []string) {
+			transaction, secretKey, curve, configuredTransaction := prepareTransaction(cmd)
+
+			// keychain services are resolved from the network, which is not available when signing offline
+			if configuredTransaction.serviceName != "" {
+				CheckError(errors.New("signing a transaction for a keychain service requires network access, use send-transaction instead"))
+			}
+
+			// the index can't be fetched offline, so it must be set by the flag or the configuration file
+			if !configuredTransaction.indexSet {
+				CheckError(errors.New("the index of the transaction can't be fetched offline, set it with --index or in the configuration file"))
+			}
+			storageNouncePublicKey, _ := cmd.Flags().GetString("storage-nonce-public-key")
+
+			err := tuiutils.SignTransaction(transaction, secretKey, curve, configuredTransaction.index, storageNouncePublicKey, configuredTransaction.signer())
+			CheckError(err)
+
+			// without output file, the transaction file is the result of the command
+			outputFile, _ := cmd.Flags().GetString("output-file")
+			if outputFile == "" {
+				file, err := tuiutils.NewTransactionFile(transaction)
+				CheckError(err)
+				printResult(signedTransactionFile{TransactionFile: file, transaction: transaction})
+				return
+			}
+			err = tuiutils.WriteTransactionFile(transaction, outputFile)
+			CheckError(err)
+			printResult(WrittenTransaction{Address: strings.ToUpper(hex.EncodeToString(transaction.Address)), File: outputFile})
+		},
+	}
+
+	setupTransactionFlags(signTransactionCmd)
+	signTransactionCmd.Flags().String("output-file", "", "The file location where the signed transaction is written (default to stdout)")
+	signTransactionCmd.Flags().String("storage-nonce-public-key", "", "Storage nonce public key of the network (required if the transaction contains a smart contract)")
+	setDefaultOutput(signTransactionCmd, jsonOutput)
+	return signTransactionCmd
+}
+
+func (t WrittenTransaction) describe() string {
+	return fmt.Sprintf("Transaction %s written to %s\n", t.Address, t.File)
+}
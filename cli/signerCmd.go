@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+)
+
+func GetSignerCmd() *cobra.Command {
+	signerCmd := &cobra.Command{
+		Use:   "signer",
+		Short: "Answer a request of an external signer with the keys of a seed",
+		Long: `Answer a request of an external signer with the keys of a seed, as a local stand-in of an HSM bridge or a vault service.
+
+The commands passing --signer <command> run the command with the shell for each operation, instead of deriving the keys
+from the seed. The command reads one JSON request on its standard input, and writes one JSON response on its standard output
+(the values are in hexadecimal):
+  {"version":1,"operation":"public_key","curve":"ED25519","index":1}
+    -> {"public_key":"..."}
+  {"version":1,"operation":"sign","curve":"ED25519","index":0,"payload":"..."}
+    -> {"public_key":"...","signature":"..."}
+  {"version":1,"operation":"decrypt","curve":"ED25519","index":0,"ciphertext":"..."}
+    -> {"plaintext":"..."}
+A failed request is answered with {"error":"..."}. The decrypt operation is only used by the keychain commands, to decrypt
+the keychain with the access key.
+
+For example: archethic-cli send-transaction --signer "archethic-cli signer --keystore main" ...
+The standard input is the request, so the passphrase of the keystore is read from ` + tuiutils.KeystorePassphraseEnv + `.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			seed, err := getSignerSeed(cmd)
+			if err != nil {
+				json.NewEncoder(os.Stdout).Encode(tuiutils.SignerResponse{Error: err.Error()})
+				os.Exit(1)
+			}
+			if err := tuiutils.ServeSignerRequest(tuiutils.SeedSigner(seed), os.Stdin, os.Stdout); err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+	signerCmd.Flags().String("seed", "", "Seed, or a secret reference other than the standard input")
+	signerCmd.Flags().Bool("ssh", false, "Enable SSH key mode")
+	signerCmd.Flags().String("ssh-path", GetFirstSshKeyDefaultPath(), "Path to ssh key")
+	signerCmd.Flags().Bool("mnemonic", false, "Enable mnemonic words for seed")
+	signerCmd.MarkFlagsMutuallyExclusive("seed", "ssh")
+	signerCmd.MarkFlagsMutuallyExclusive("seed", "ssh-path")
+	signerCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh")
+	signerCmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh-path")
+	signerCmd.MarkFlagsMutuallyExclusive("mnemonic", "seed")
+	setupKeystoreFlag(signerCmd)
+	setupMnemonicFlags(signerCmd)
+	// the seed is always explicit, a profile could make the signer call itself
+	disableProfile(signerCmd)
+	return signerCmd
+}
+
+func getSignerSeed(cmd *cobra.Command) ([]byte, error) {
+	if err := validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "seed", "mnemonic"); err != nil {
+		return nil, err
+	}
+	if seed, _ := cmd.Flags().GetString("seed"); seed == "-" {
+		return nil, errors.New("the standard input is the request, it can't give the seed")
+	}
+	seed, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "seed", "mnemonic")
+	if err != nil {
+		return nil, err
+	}
+	if len(seed) == 0 {
+		return nil, errors.New("the seed is empty")
+	}
+	return seed, nil
+}
@@ -26,14 +26,18 @@ func extractTransactionFromInputFile(config string) (ConfiguredTransaction, Send
 	if err != nil {
 		return ConfiguredTransaction{}, SendTransactionData{}, err
 	}
-	seedByte, err := archethic.MaybeConvertToHex(data.AccessSeed)
+	configuredTransaction, err := configuredTransactionFromData(data)
+	return configuredTransaction, data, err
+}
+
+func configuredTransactionFromData(data SendTransactionData) (ConfiguredTransaction, error) {
+	seedByte, err := tuiutils.ParseSeed(data.AccessSeed, tuiutils.MnemonicOptions{})
 	if err != nil {
-		return ConfiguredTransaction{}, data, err
+		return ConfiguredTransaction{}, err
 	}
 
-	return ConfiguredTransaction{
+	configuredTransaction := ConfiguredTransaction{
 		accessSeed:     seedByte,
-		index:          data.Index,
 		ucoTransfers:   data.UcoTransfers,
 		tokenTransfers: data.TokenTransfers,
 		recipients:     data.Recipients,
@@ -41,12 +45,19 @@ func extractTransactionFromInputFile(config string) (ConfiguredTransaction, Send
 		content:        []byte(data.Content),
 		smartContract:  data.SmartContract,
 		serviceName:    data.ServiceName,
-	}, data, nil
-
+	}
+	if data.Index != nil {
+		configuredTransaction.index = *data.Index
+		configuredTransaction.indexSet = true
+	}
+	return configuredTransaction, nil
 }
 
 func extractTransactionFromInputFlags(cmd *cobra.Command) (ConfiguredTransaction, error) {
 	index, _ := cmd.Flags().GetInt("index")
+	if index < 0 {
+		return ConfiguredTransaction{}, fmt.Errorf("invalid index %d: must not be negative", index)
+	}
 	serviceName, _ := cmd.Flags().GetString("serviceName")
 
 	// extract uco transfers
@@ -86,6 +97,17 @@ func extractTransactionFromInputFlags(cmd *cobra.Command) (ConfiguredTransaction
 		})
 	}
 
+	// extract uco and token transfers from the csv file
+	transfersCsv, _ := cmd.Flags().GetString("transfers-csv")
+	if transfersCsv != "" {
+		csvUcoTransfers, csvTokenTransfers, err := readTransfersCsv(transfersCsv)
+		if err != nil {
+			return ConfiguredTransaction{}, err
+		}
+		ucoTransfers = append(ucoTransfers, csvUcoTransfers...)
+		tokenTransfers = append(tokenTransfers, csvTokenTransfers...)
+	}
+
 	// extract ownerships
 	ownershipsStr, _ := cmd.Flags().GetStringToString("ownership")
 	var ownerships []Ownership
@@ -159,15 +181,23 @@ func extractTransactionFromInputFlags(cmd *cobra.Command) (ConfiguredTransaction
 
 	err = validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
 	var accessSeedBytes []byte
+	var externalSigner tuiutils.Signer
 	// if no flag have been passed to configure the accessSeed, maybe the config is set in the config file
 	if err == nil {
-		accessSeedBytes, err = tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
-		cobra.CheckErr(err)
+		signer, err := tuiutils.GetSigner(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+		CheckError(err)
+		if seed, ok := signer.(tuiutils.SeedSigner); ok {
+			accessSeedBytes = seed
+		} else {
+			externalSigner = signer
+		}
 	}
 
 	return ConfiguredTransaction{
 		accessSeed:     accessSeedBytes,
+		externalSigner: externalSigner,
 		index:          uint(index),
+		indexSet:       cmd.Flags().Changed("index"),
 		ucoTransfers:   ucoTransfers,
 		tokenTransfers: tokenTransfers,
 		recipients:     recipients,
@@ -180,8 +210,9 @@ func extractTransactionFromInputFlags(cmd *cobra.Command) (ConfiguredTransaction
 
 // override file configuration by flag configuration
 func combineTransactions(fileConfig ConfiguredTransaction, flagConfig ConfiguredTransaction) ConfiguredTransaction {
-	if flagConfig.index != 0 {
+	if flagConfig.indexSet {
 		fileConfig.index = flagConfig.index
+		fileConfig.indexSet = true
 	}
 
 	if len(flagConfig.ucoTransfers) > 0 {
@@ -216,6 +247,11 @@ func combineTransactions(fileConfig ConfiguredTransaction, flagConfig Configured
 		fileConfig.accessSeed = flagConfig.accessSeed
 	}
 
+	if flagConfig.externalSigner != nil {
+		fileConfig.accessSeed = nil
+		fileConfig.externalSigner = flagConfig.externalSigner
+	}
+
 	return fileConfig
 }
 
@@ -323,10 +359,20 @@ func mapOwnership(ownerships map[string]string) map[string][]string {
 	return result
 }
 
-func extractAndPrepareTransaction(cmd *cobra.Command, args []string, action func(*archethic.TransactionBuilder, []byte, archethic.Curve, bool, string, uint, string, string, []byte) (interface{}, error)) {
-	secretKey := make([]byte, 32)
-	rand.Read(secretKey)
+// prepareTransaction merges the file and flag configurations and builds the unsigned transaction.
+// It doesn't need any network access.
+func prepareTransaction(cmd *cobra.Command) (*archethic.TransactionBuilder, []byte, archethic.Curve, ConfiguredTransaction) {
+	configuredTransaction, _ := loadTransactionConfiguration(cmd)
 
+	txType, err := transactionType.GetTransactionType()
+	CheckError(err)
+
+	transaction, secretKey, curve := buildConfiguredTransaction(configuredTransaction, txType)
+	return transaction, secretKey, curve, configuredTransaction
+}
+
+// loadTransactionConfiguration merges the configuration file (if any) with the flags
+func loadTransactionConfiguration(cmd *cobra.Command) (ConfiguredTransaction, SendTransactionData) {
 	config, _ := cmd.Flags().GetString("config")
 	var fileConfig, flagConfig, configuredTransaction ConfiguredTransaction
 	var sendTransactionData SendTransactionData
@@ -342,44 +388,135 @@ func extractAndPrepareTransaction(cmd *cobra.Command, args []string, action func
 		if sendTransactionData.TransactionType != "" {
 			transactionType.Set(sendTransactionData.TransactionType)
 		}
-		cobra.CheckErr(err)
+		CheckError(err)
+		if len(fileConfig.accessSeed) > 0 {
+			// the seed of the configuration file takes precedence over the seed of the profile
+			resetProfileSeed(cmd.Flags())
+		}
 	}
 	flagConfig, err = extractTransactionFromInputFlags(cmd)
-	cobra.CheckErr(err)
+	CheckError(err)
 
 	// merging the config based on file with the one based on flags
 	configuredTransaction = combineTransactions(fileConfig, flagConfig)
 
-	err = checkAccessSeed(configuredTransaction.accessSeed)
-	cobra.CheckErr(err)
+	if configuredTransaction.externalSigner == nil {
+		err = checkAccessSeed(configuredTransaction.accessSeed)
+		CheckError(err)
+	}
 
-	curve, err := ellipticCurve.GetCurve()
-	cobra.CheckErr(err)
+	return configuredTransaction, sendTransactionData
+}
 
-	txType, err := transactionType.GetTransactionType()
-	cobra.CheckErr(err)
+func buildConfiguredTransaction(configuredTransaction ConfiguredTransaction, txType archethic.TransactionType) (*archethic.TransactionBuilder, []byte, archethic.Curve) {
+	secretKey := make([]byte, 32)
+	rand.Read(secretKey)
+
+	curve, err := ellipticCurve.GetCurve()
+	CheckError(err)
 
 	transaction, err := configureTransaction(configuredTransaction, txType, secretKey)
-	cobra.CheckErr(err)
+	CheckError(err)
+
+	return transaction, secretKey, curve
+}
+
+// SentTransaction is the result of the commands sending a transaction
+type SentTransaction struct {
+	Address     string `json:"address"`
+	Status      string `json:"status"`
+	ExplorerURL string `json:"explorer_url"`
+}
 
+// TransactionFee is the estimated fee of a transaction, with the rates used to convert it to fiat currencies
+type TransactionFee struct {
+	Fee   string   `json:"fee"`
+	Rates FeeRates `json:"rates"`
+}
+
+type FeeRates struct {
+	Eur float32 `json:"eur"`
+	Usd float32 `json:"usd"`
+}
+
+type transactionAction func(*archethic.TransactionBuilder, []byte, archethic.Curve, bool, string, uint, string, string, tuiutils.Signer) (commandResult, error)
+
+func extractAndPrepareTransaction(cmd *cobra.Command, args []string, action transactionAction) {
+	transaction, secretKey, curve, configuredTransaction := prepareTransaction(cmd)
+	printResult(runTransactionAction(cmd, transaction, secretKey, curve, configuredTransaction, action))
+}
+
+// runTransactionAction fetches the index (if needed) and the storage nonce public key, then calls the action and returns its result
+func runTransactionAction(cmd *cobra.Command, transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, configuredTransaction ConfiguredTransaction, action transactionAction) commandResult {
 	serviceMode := configuredTransaction.serviceName != ""
 
 	client := archethic.NewAPIClient(endpoint.String())
 
 	// if no index is provided and not in serviceMode, get the last transaction index
 	if !cmd.Flags().Changed("index") && !serviceMode {
-		address, err := archethic.DeriveAddress([]byte(configuredTransaction.accessSeed), 0, curve, archethic.SHA256)
-		cobra.CheckErr(err)
+		address, err := tuiutils.SignerAddress(configuredTransaction.signer(), 0, curve, archethic.SHA256)
+		CheckError(err)
 		addressHex := hex.EncodeToString(address)
 		configuredTransaction.index = client.GetLastTransactionIndex(addressHex)
 	}
 
 	storageNouncePublicKey, err := client.GetStorageNoncePublicKey()
-	cobra.CheckErr(err)
+	CheckError(err)
+
+	result, err := action(transaction, secretKey, curve, serviceMode, endpoint.String(), configuredTransaction.index, configuredTransaction.serviceName, storageNouncePublicKey, configuredTransaction.signer())
+	checkSendError(err)
+	return result
+}
+
+func sendTransactionAction(cmd *cobra.Command) transactionAction {
+	return func(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, index uint, serviceName string, storageNouncePublicKey string, signer tuiutils.Signer) (commandResult, error) {
+		checks, err := getTransactionChecks(cmd)
+		if err != nil {
+			return nil, err
+		}
+		waitConfirmations, timeout := getConfirmationFlags(cmd, 0)
+		explorerUrl, err := tuiutils.SendTransaction(transaction, secretKey, curve, serviceMode, endpoint, index, serviceName, storageNouncePublicKey, signer, checks, waitConfirmations, timeout)
+		if err != nil {
+			return nil, err
+		}
+		return newSentTransaction(transaction, explorerUrl, waitConfirmations), nil
+	}
+}
 
-	result, err := action(transaction, secretKey, curve, serviceMode, endpoint.String(), configuredTransaction.index, configuredTransaction.serviceName, storageNouncePublicKey, configuredTransaction.accessSeed)
-	cobra.CheckErr(err)
-	fmt.Println(result)
+func getTransactionFeeAction(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, index uint, serviceName string, storageNouncePublicKey string, signer tuiutils.Signer) (commandResult, error) {
+	fee, err := tuiutils.GetTransactionFee(transaction, secretKey, curve, serviceMode, endpoint, index, serviceName, storageNouncePublicKey, signer)
+	if err != nil {
+		return nil, err
+	}
+	return newTransactionFee(fee), nil
+}
+
+func newSentTransaction(transaction *archethic.TransactionBuilder, explorerUrl string, waitConfirmations uint) SentTransaction {
+	status := tuiutils.TransactionSent
+	if waitConfirmations > 0 {
+		status = tuiutils.TransactionConfirmed
+	}
+	return SentTransaction{
+		Address:     strings.ToUpper(hex.EncodeToString(transaction.Address)),
+		Status:      status.String(),
+		ExplorerURL: explorerUrl,
+	}
+}
+
+func (t SentTransaction) describe() string {
+	return t.ExplorerURL + "\n"
+}
+
+func newTransactionFee(fee archethic.Fee) TransactionFee {
+	return TransactionFee{
+		Fee:   archethic.FormatBigInt(fee.Fee, 8),
+		Rates: FeeRates{Eur: fee.Rates.Eur, Usd: fee.Rates.Usd},
+	}
+}
+
+func (f TransactionFee) describe() string {
+	fee, _ := strconv.ParseFloat(f.Fee, 64)
+	return fmt.Sprintf("Transaction fee: %s UCO (~ $%f) (~ %f€)\n", f.Fee, fee*float64(f.Rates.Usd), fee*float64(f.Rates.Eur))
 }
 
 func GetSendTransactionCmd() *cobra.Command {
@@ -387,13 +524,13 @@ func GetSendTransactionCmd() *cobra.Command {
 		Use:   "send-transaction",
 		Short: "Send transaction",
 		Run: func(cmd *cobra.Command, args []string) {
-			extractAndPrepareTransaction(cmd, args, func(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, index uint, serviceName string, storageNouncePublicKey string, seed []byte) (interface{}, error) {
-				return tuiutils.SendTransaction(transaction, secretKey, curve, serviceMode, endpoint, index, serviceName, storageNouncePublicKey, seed)
-			})
+			extractAndPrepareTransaction(cmd, args, sendTransactionAction(cmd))
 		},
 	}
 
 	setupTransactionFlags(sendTransactionCmd)
+	setupFeeCheckFlags(sendTransactionCmd)
+	setupConfirmationFlags(sendTransactionCmd, "default to 0, only wait until the transaction is sent")
 	return sendTransactionCmd
 }
 
@@ -402,9 +539,7 @@ func GetGetTransactionFeeCmd() *cobra.Command {
 		Use:   "get-transaction-fee",
 		Short: "Get transaction fee",
 		Run: func(cmd *cobra.Command, args []string) {
-			extractAndPrepareTransaction(cmd, args, func(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, index uint, serviceName string, storageNouncePublicKey string, seed []byte) (interface{}, error) {
-				return tuiutils.GetTransactionFeeJson(transaction, secretKey, curve, serviceMode, endpoint, index, serviceName, storageNouncePublicKey, seed)
-			})
+			extractAndPrepareTransaction(cmd, args, getTransactionFeeAction)
 		},
 	}
 
@@ -424,11 +559,15 @@ func setupTransactionFlags(cmd *cobra.Command) {
 	cmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh")
 	cmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh-path")
 	cmd.MarkFlagsMutuallyExclusive("mnemonic", "access-seed")
+	setupKeystoreFlag(cmd)
+	setupMnemonicFlags(cmd)
+	setupSignerFlag(cmd)
 	cmd.Flags().Int("index", 0, "Index")
 	cmd.Flags().Var(&ellipticCurve, "elliptic-curve", "Elliptic Curve (ED25519|P256|SECP256K1)")
 	cmd.Flags().Var(&transactionType, "transaction-type", "Transaction Type (keychain_access|keychain|transfer|hosting|token|data|contract|code_proposal|code_approval)")
 	cmd.Flags().StringToString("uco-transfer", map[string]string{}, "UCO Transfers (format: to=amount)")
 	cmd.Flags().StringToString("token-transfer", map[string]string{}, "Token Transfers (format: to=amount,token_address,token_id)")
+	cmd.Flags().String("transfers-csv", "", "The file location of a CSV file of UCO and token transfers (columns: to,amount,token_address,token_id)")
 	// can't use StringToString for recipient because it cannot contains double quotes
 	// see https://github.com/spf13/pflag/issues/370
 	cmd.Flags().StringArray("recipient", []string{}, "Recipients (format: address=json_of_action)")
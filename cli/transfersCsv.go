@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// readTransfersCsv reads a CSV file with the columns to, amount, token_address, token_id.
+// The rows without token address are UCO transfers, the other ones are token transfers.
+// An optional header row is allowed.
+func readTransfersCsv(path string) ([]UCOTransfer, []TokenTransfer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	// the token columns can be omitted for UCO transfers
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var ucoTransfers []UCOTransfer
+	var tokenTransfers []TokenTransfer
+	firstRow := true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("transfers csv: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if firstRow && strings.EqualFold(strings.TrimSpace(record[0]), "to") {
+			firstRow = false
+			continue
+		}
+		firstRow = false
+
+		ucoTransfer, tokenTransfer, err := parseTransferRecord(record)
+		if err != nil {
+			return nil, nil, fmt.Errorf("transfers csv: line %d: %w", line, err)
+		}
+		if tokenTransfer != nil {
+			tokenTransfers = append(tokenTransfers, *tokenTransfer)
+		} else {
+			ucoTransfers = append(ucoTransfers, *ucoTransfer)
+		}
+	}
+	if len(ucoTransfers) == 0 && len(tokenTransfers) == 0 {
+		return nil, nil, errors.New("transfers csv: no transfer found")
+	}
+	return ucoTransfers, tokenTransfers, nil
+}
+
+func parseTransferRecord(record []string) (*UCOTransfer, *TokenTransfer, error) {
+	if len(record) < 2 || len(record) > 4 {
+		return nil, nil, fmt.Errorf("expected 2 to 4 columns (to, amount, token_address, token_id), got %d", len(record))
+	}
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+
+	to, err := parseAddressColumn(record[0], "to")
+	if err != nil {
+		return nil, nil, err
+	}
+	amount := record[1]
+	amountBigInt, err := archethic.ParseBigInt(amount, 8)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid amount %q: %w", amount, err)
+	}
+	if amountBigInt.Sign() <= 0 {
+		return nil, nil, fmt.Errorf("invalid amount %q: must be greater than 0", amount)
+	}
+
+	tokenAddress := ""
+	if len(record) > 2 {
+		tokenAddress = record[2]
+	}
+	tokenIdStr := ""
+	if len(record) > 3 {
+		tokenIdStr = record[3]
+	}
+
+	if tokenAddress == "" {
+		if tokenIdStr != "" {
+			return nil, nil, errors.New("token_id is set without token_address")
+		}
+		return &UCOTransfer{To: to, Amount: amount}, nil, nil
+	}
+
+	tokenAddress, err = parseAddressColumn(tokenAddress, "token_address")
+	if err != nil {
+		return nil, nil, err
+	}
+	var tokenId uint64
+	if tokenIdStr != "" {
+		tokenId, err = strconv.ParseUint(tokenIdStr, 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid token_id %q: must be a positive integer", tokenIdStr)
+		}
+	}
+	return nil, &TokenTransfer{
+		To:           to,
+		Amount:       amount,
+		TokenAddress: tokenAddress,
+		TokenID:      uint(tokenId),
+	}, nil
+}
+
+func parseAddressColumn(value string, column string) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf("%s is empty", column)
+	}
+	address, err := hex.DecodeString(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s %q: not a hex encoded address", column, value)
+	}
+	return hex.EncodeToString(address), nil
+}
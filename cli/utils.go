@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"strings"
 
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
@@ -20,7 +23,7 @@ var (
 type SendTransactionData struct {
 	Endpoint        string          `yaml:"endpoint"`
 	AccessSeed      string          `yaml:"access_seed"`
-	Index           uint            `yaml:"index"`
+	Index           *uint           `yaml:"index"`
 	EllipticCurve   string          `yaml:"elliptic_curve"`
 	TransactionType string          `yaml:"transaction_type"`
 	UcoTransfers    []UCOTransfer   `yaml:"uco_transfers,omitempty"`
@@ -30,6 +33,17 @@ type SendTransactionData struct {
 	Content         string          `yaml:"content,omitempty"`
 	SmartContract   string          `yaml:"smart_contract,omitempty"`
 	ServiceName     string          `yaml:"serviceName,omitempty"`
+	Token           *TokenData      `yaml:"token,omitempty"`
+}
+
+type TokenData struct {
+	Name       string                   `yaml:"name"`
+	Symbol     string                   `yaml:"symbol"`
+	Supply     string                   `yaml:"supply"`
+	Type       string                   `yaml:"type"`
+	Decimals   *uint                    `yaml:"decimals,omitempty"`
+	Properties map[string]interface{}   `yaml:"properties,omitempty"`
+	Collection []map[string]interface{} `yaml:"collection,omitempty"`
 }
 
 type UCOTransfer struct {
@@ -56,8 +70,12 @@ type Recipient struct {
 }
 
 type ConfiguredTransaction struct {
-	accessSeed     []byte
+	accessSeed []byte
+	// externalSigner holds the keys of the chain in place of the access seed (see tuiutils.ExternalSigner)
+	externalSigner tuiutils.Signer
 	index          uint
+	// indexSet reports whether the index is configured, by the file or the flag, as 0 is a valid index
+	indexSet       bool
 	ucoTransfers   []UCOTransfer
 	tokenTransfers []TokenTransfer
 	recipients     []Recipient
@@ -76,20 +94,7 @@ const (
 )
 
 func (e *EndpointCLI) String() string {
-	switch *e {
-	case local:
-		return "http://localhost:4000"
-	case testnet:
-		return "https://testnet.archethic.net"
-	case mainnet:
-		return "https://mainnet.archethic.net"
-	default:
-		_, err := url.Parse(string(*e))
-		if err != nil {
-			return ""
-		}
-		return string(*e)
-	}
+	return tuiutils.EndpointURL(string(*e))
 }
 
 func (e *EndpointCLI) Set(value string) error {
@@ -327,15 +332,126 @@ func (tt *TransactionTypeCLI) GetTransactionType() (archethic.TransactionType, e
 }
 
 func validateRequiredFlags(flags *pflag.FlagSet, sshFlagKey, sshPathFlagKey, seedKey, mnemonicFlag string) error {
-	// validate if sshFlagKey or sshPathFlagKey or seedKey is set
-	if !flags.Changed(sshFlagKey) && !flags.Changed(sshPathFlagKey) && !flags.Changed(seedKey) && !flags.Changed(mnemonicFlag) {
-		errorMessage := fmt.Sprintf("required flag(s) \"%s\" or \"%s\" or \"%s\" or \"%s\" not set", sshFlagKey, sshPathFlagKey, seedKey, mnemonicFlag)
-		return errors.New(errorMessage)
+	// validate if sshFlagKey or sshPathFlagKey or seedKey or the keystore flag (or the signer flag, if the command has it) is set
+	hasSigner := flags.Lookup(tuiutils.SignerFlag) != nil
+	if !flags.Changed(sshFlagKey) && !flags.Changed(sshPathFlagKey) && !flags.Changed(seedKey) && !flags.Changed(mnemonicFlag) &&
+		!flags.Changed(tuiutils.KeystoreFlag) && !(hasSigner && flags.Changed(tuiutils.SignerFlag)) {
+		errorMessage := fmt.Sprintf("required flag(s) \"%s\" or \"%s\" or \"%s\" or \"%s\" or \"%s\"", sshFlagKey, sshPathFlagKey, seedKey, mnemonicFlag, tuiutils.KeystoreFlag)
+		if hasSigner {
+			errorMessage += fmt.Sprintf(" or \"%s\"", tuiutils.SignerFlag)
+		}
+		return errors.New(errorMessage + " not set")
 	}
 	return nil
 }
 
+// signer returns the signer of the transaction: the external signer, or the signer of the access seed
+func (c ConfiguredTransaction) signer() tuiutils.Signer {
+	if c.externalSigner != nil {
+		return c.externalSigner
+	}
+	return tuiutils.SeedSigner(c.accessSeed)
+}
+
+// setupKeystoreFlag adds the --keystore flag passing the seed of the command, exclusive with its other seed flags
+func setupKeystoreFlag(cmd *cobra.Command) {
+	cmd.Flags().String(tuiutils.KeystoreFlag, "", "Name of the keystore entry of the seed (see the keystore command)")
+	for _, name := range seedFlags {
+		if name != tuiutils.KeystoreFlag && cmd.Flags().Lookup(name) != nil {
+			cmd.MarkFlagsMutuallyExclusive(tuiutils.KeystoreFlag, name)
+		}
+	}
+}
+
+// setupMnemonicFlags adds the flags of the options of the mnemonics passed as seed
+func setupMnemonicFlags(cmd *cobra.Command) {
+	cmd.Flags().String(tuiutils.MnemonicLanguageFlag, "", "Language of the mnemonic ("+strings.Join(tuiutils.MnemonicLanguages(), "|")+"), every language is tried by default")
+	cmd.Flags().String(tuiutils.MnemonicPassphraseFlag, "", "BIP39 passphrase of the mnemonic, or a secret reference: the seed is then the BIP39 seed instead of the entropy of the mnemonic")
+	for _, name := range []string{"ssh", "ssh-path", tuiutils.KeystoreFlag} {
+		if cmd.Flags().Lookup(name) != nil {
+			cmd.MarkFlagsMutuallyExclusive(tuiutils.MnemonicLanguageFlag, name)
+			cmd.MarkFlagsMutuallyExclusive(tuiutils.MnemonicPassphraseFlag, name)
+		}
+	}
+}
+
+// setupSignerFlag adds the --signer flag passing the command of an external signer, exclusive with the seed flags of the command
+func setupSignerFlag(cmd *cobra.Command) {
+	cmd.Flags().String(tuiutils.SignerFlag, "", "Command of the external signer holding the keys, in place of the seed (see the signer command)")
+	for _, name := range seedFlags {
+		if name != tuiutils.SignerFlag && cmd.Flags().Lookup(name) != nil {
+			cmd.MarkFlagsMutuallyExclusive(tuiutils.SignerFlag, name)
+		}
+	}
+}
+
 func GetFirstSshKeyDefaultPath() string {
 	home, _ := os.UserHomeDir()
 	return home + "/.ssh/id_ed25519"
 }
+
+// Exit codes returned when a transaction didn't reach the expected number of confirmations
+const (
+	ExitCodeTransportError = 2
+	ExitCodeRejected       = 3
+	ExitCodeTimeout        = 4
+	ExitCodeUnconfirmed    = 5
+)
+
+func setupConfirmationFlags(cmd *cobra.Command, defaultDescription string) {
+	cmd.Flags().Uint("wait-confirmations", 0, "Number of replication confirmations to wait for before returning ("+defaultDescription+")")
+	cmd.Flags().Uint("timeout", tuiutils.DefaultTimeout, "Number of seconds to wait for the confirmations")
+}
+
+// getConfirmationFlags returns the number of confirmations to wait for and the timeout,
+// defaultConfirmations is used if the wait-confirmations flag is not set
+func getConfirmationFlags(cmd *cobra.Command, defaultConfirmations uint) (uint, uint) {
+	waitConfirmations, _ := cmd.Flags().GetUint("wait-confirmations")
+	if !cmd.Flags().Changed("wait-confirmations") {
+		waitConfirmations = defaultConfirmations
+	}
+	timeout, _ := cmd.Flags().GetUint("timeout")
+	return waitConfirmations, timeout
+}
+
+func setupFeeCheckFlags(cmd *cobra.Command) {
+	cmd.Flags().String("max-fee", "", "Maximum fee of the transaction, in UCO or in fiat (for instance 0.5, 0.5UCO, 1USD or 1EUR), the transaction is not sent if its fee is higher")
+	cmd.Flags().Bool("skip-balance-check", false, "Don't check that the balance of the chain covers the transfers and the fee before sending the transaction")
+}
+
+// getTransactionChecks returns the checks done before sending the transaction
+func getTransactionChecks(cmd *cobra.Command) (tuiutils.TransactionChecks, error) {
+	skipBalanceCheck, _ := cmd.Flags().GetBool("skip-balance-check")
+	checks := tuiutils.TransactionChecks{CheckBalance: !skipBalanceCheck}
+	maxFee, _ := cmd.Flags().GetString("max-fee")
+	if maxFee != "" {
+		parsedMaxFee, err := tuiutils.ParseMaxFee(maxFee)
+		if err != nil {
+			return checks, err
+		}
+		checks.MaxFee = &parsedMaxFee
+	}
+	return checks, nil
+}
+
+// checkSendError exits with a distinct exit code depending on why the transaction was not confirmed
+func checkSendError(err error) {
+	var sendError tuiutils.SendTransactionError
+	if !errors.As(err, &sendError) {
+		CheckError(err)
+		return
+	}
+	commandError := newCommandError(err)
+	commandError.Status = sendError.Status.String()
+	printError(commandError, err)
+	switch sendError.Status {
+	case tuiutils.TransactionRejected:
+		os.Exit(ExitCodeRejected)
+	case tuiutils.TransactionTimeout:
+		os.Exit(ExitCodeTimeout)
+	case tuiutils.TransactionUnconfirmed:
+		os.Exit(ExitCodeUnconfirmed)
+	default:
+		os.Exit(ExitCodeTransportError)
+	}
+}
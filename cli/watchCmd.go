@@ -0,0 +1,316 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+)
+
+type WatchEvent struct {
+	Event       string            `json:"event"`
+	Chain       string            `json:"chain"`
+	Transaction *ChainTransaction `json:"transaction,omitempty"`
+	Transfer    *WatchTransfer    `json:"transfer,omitempty"`
+}
+
+type WatchTransfer struct {
+	To           string `json:"to"`
+	From         string `json:"from"`
+	Amount       string `json:"amount"`
+	TokenAddress string `json:"token_address,omitempty"`
+	TokenId      uint   `json:"token_id,omitempty"`
+	Timestamp    string `json:"timestamp"`
+}
+
+// chainWatcher follows a chain and sends an event for each new transaction or incoming transfer
+type chainWatcher struct {
+	input        chainInput
+	address      string
+	lastAddress  string
+	chainLength  uint
+	seenInputs   map[string]bool
+	subscription *tuiutils.Subscription
+	// nextAddress is the address of the next transaction of the chain, whose subscription is in progress or open
+	nextAddress string
+	// subscribed receives the subscriptions once connected, nil if the websocket isn't available
+	subscribed  chan watchSubscription
+	noWebsocket bool
+	// refresh is notified by the subscriptions when the next transaction of the chain is confirmed
+	refresh chan struct{}
+	events  chan<- WatchEvent
+}
+
+type watchSubscription struct {
+	address      string
+	subscription *tuiutils.Subscription
+}
+
+func GetWatchCmd() *cobra.Command {
+	watchCmd := &cobra.Command{
+		Use:   "watch [address...]",
+		Short: "Print the new transactions and incoming transfers of chains as they are validated",
+		Run: func(cmd *cobra.Command, args []string) {
+			interval, _ := cmd.Flags().GetDuration("interval")
+			if interval <= 0 {
+				CheckError(errors.New("--interval must be greater than 0"))
+			}
+			hook, _ := cmd.Flags().GetString("exec")
+
+			for _, arg := range args {
+				CheckError(cmd.Flags().Set("address", arg))
+			}
+			inputs, err := getChainInputs(cmd)
+			CheckError(err)
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			events := make(chan WatchEvent)
+			for _, input := range inputs {
+				watcher, err := newChainWatcher(input, events)
+				CheckError(err)
+				fmt.Fprintf(os.Stderr, "Watching %s from %s (%d transactions)\n", input.label, watcher.lastAddress, watcher.chainLength)
+				go watcher.run(ctx, interval)
+			}
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event := <-events:
+					printEvent(event)
+					if hook != "" {
+						runWatchHook(hook, event)
+					}
+				}
+			}
+		},
+	}
+
+	setupChainInputFlags(watchCmd)
+	watchCmd.Flags().Duration("interval", 10*time.Second, "Interval between two checks of the chains")
+	watchCmd.Flags().String("exec", "", "Command run by the shell for each event, with the event in JSON on its standard input")
+	return watchCmd
+}
+
+// newChainWatcher loads the current state of the chain, the existing transactions and transfers don't send any event
+func newChainWatcher(input chainInput, events chan<- WatchEvent) (*chainWatcher, error) {
+	watcher := &chainWatcher{
+		input:      input,
+		seenInputs: make(map[string]bool),
+		subscribed: make(chan watchSubscription, 1),
+		refresh:    make(chan struct{}, 1),
+		events:     events,
+	}
+	var err error
+	if input.deriveAddress != nil {
+		watcher.address, err = input.deriveAddress(0)
+	} else {
+		watcher.address, err = input.resolve()
+	}
+	if err != nil {
+		return nil, err
+	}
+	watcher.lastAddress, watcher.chainLength, err = tuiutils.GetChainState(endpoint.String(), watcher.address)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := tuiutils.GetTransactionInputs(endpoint.String(), watcher.lastAddress)
+	if err != nil {
+		return nil, err
+	}
+	for _, input := range inputs {
+		watcher.seenInputs[transactionInputKey(input)] = true
+	}
+	return watcher, nil
+}
+
+func (w *chainWatcher) run(ctx context.Context, interval time.Duration) {
+	w.subscribe()
+	defer func() {
+		if w.subscription != nil {
+			w.subscription.Close()
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case s := <-w.subscribed:
+			w.setSubscription(s)
+			continue
+		case <-ticker.C:
+		case <-w.refresh:
+		}
+		if err := w.update(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", w.input.label, err)
+		}
+	}
+}
+
+// subscribe waits in the background for the confirmation of the next transaction of the chain, when its
+// address can be derived. The node only notifies the confirmation of a given transaction address, so the
+// chains passed by an address and the incoming transfers are only checked at each interval.
+func (w *chainWatcher) subscribe() {
+	if w.input.deriveAddress == nil || w.noWebsocket {
+		return
+	}
+	nextAddress, err := w.input.deriveAddress(w.chainLength + 1)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", w.input.label, err)
+		return
+	}
+	w.nextAddress = nextAddress
+	go func() {
+		subscription, err := tuiutils.SubscribeTransactionConfirmed(endpoint.String(), nextAddress, func() {
+			select {
+			case w.refresh <- struct{}{}:
+			default:
+			}
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s, checking the chain every interval instead\n", w.input.label, err)
+			subscription = nil
+		}
+		w.subscribed <- watchSubscription{address: nextAddress, subscription: subscription}
+	}()
+}
+
+// setSubscription replaces the open subscription, unless the chain has changed since the subscription started
+func (w *chainWatcher) setSubscription(s watchSubscription) {
+	if s.subscription == nil {
+		w.noWebsocket = true
+		return
+	}
+	if s.address != w.nextAddress {
+		s.subscription.Close()
+		return
+	}
+	if w.subscription != nil {
+		w.subscription.Close()
+	}
+	w.subscription = s.subscription
+}
+
+// update sends the events of the transactions and transfers since the last update
+func (w *chainWatcher) update(ctx context.Context) error {
+	lastAddress, chainLength, err := tuiutils.GetChainState(endpoint.String(), w.address)
+	if err != nil {
+		return err
+	}
+	if chainLength > w.chainLength {
+		transactions, _, err := tuiutils.GetTransactionChain(endpoint.String(), lastAddress, w.chainLength, 0)
+		if err != nil {
+			return err
+		}
+		for _, tx := range transactions {
+			chainTransaction := newChainTransaction(tx)
+			if !w.send(ctx, WatchEvent{Event: "transaction", Chain: w.input.label, Transaction: &chainTransaction}) {
+				return nil
+			}
+		}
+		w.lastAddress = lastAddress
+		w.chainLength = chainLength
+		w.subscribe()
+	}
+
+	inputs, err := tuiutils.GetTransactionInputs(endpoint.String(), w.lastAddress)
+	if err != nil {
+		return err
+	}
+	// the inputs which no longer come back are forgotten, so the seen inputs don't grow with the watch
+	seenInputs := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		key := transactionInputKey(input)
+		seenInputs[key] = true
+		if w.seenInputs[key] {
+			continue
+		}
+		transfer := newChainTransfer(input.ChainTransfer)
+		event := WatchEvent{Event: "transfer", Chain: w.input.label, Transfer: &WatchTransfer{
+			To:           w.lastAddress,
+			From:         transfer.Address,
+			Amount:       transfer.Amount,
+			TokenAddress: transfer.TokenAddress,
+			TokenId:      transfer.TokenId,
+			Timestamp:    input.Timestamp.Format(time.RFC3339),
+		}}
+		if !w.send(ctx, event) {
+			return nil
+		}
+	}
+	w.seenInputs = seenInputs
+	return nil
+}
+
+func (w *chainWatcher) send(ctx context.Context, event WatchEvent) bool {
+	select {
+	case w.events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func transactionInputKey(input tuiutils.TransactionInput) string {
+	return fmt.Sprintf("%s/%s/%s/%d/%d", input.Address, input.Amount, input.TokenAddress, input.TokenId, input.Timestamp.Unix())
+}
+
+// address returns the address concerned by the event
+func (e WatchEvent) address() string {
+	if e.Transaction != nil {
+		return e.Transaction.Address
+	}
+	return e.Transfer.To
+}
+
+func (event WatchEvent) describe() string {
+	var b strings.Builder
+	if tx := event.Transaction; tx != nil {
+		fmt.Fprintf(&b, "[%s] transaction #%d %s %s, fee: %s UCO, %s\n", event.Chain, tx.Index, tx.Type, tx.Address, tx.Fee, tx.Status)
+		for _, transfer := range tx.TransfersOut {
+			fmt.Fprintf(&b, "  out: %s to %s\n", describeChainTransfer(transfer), transfer.Address)
+		}
+		return b.String()
+	}
+	transfer := event.Transfer
+	asset := describeChainTransfer(ChainTransfer{Amount: transfer.Amount, TokenAddress: transfer.TokenAddress, TokenId: transfer.TokenId})
+	fmt.Fprintf(&b, "[%s] transfer of %s from %s to %s\n", event.Chain, asset, transfer.From, transfer.To)
+	return b.String()
+}
+
+// runWatchHook runs the command of --exec for an event, its failure doesn't stop the watch
+func runWatchHook(hook string, event WatchEvent) {
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "exec %q: %s\n", hook, err)
+		return
+	}
+
+	hookCmd := exec.Command("sh", "-c", hook)
+	hookCmd.Stdin = bytes.NewReader(eventBytes)
+	hookCmd.Stdout = os.Stdout
+	hookCmd.Stderr = os.Stderr
+	hookCmd.Env = append(os.Environ(),
+		"ARCHETHIC_EVENT="+event.Event,
+		"ARCHETHIC_CHAIN="+event.Chain,
+		"ARCHETHIC_ADDRESS="+strings.ToUpper(event.address()),
+	)
+	if err := hookCmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "exec %q: %s\n", hook, err)
+	}
+}
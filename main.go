@@ -15,7 +15,7 @@ var rootCmd = &cobra.Command{
 		ssh, _ := cmd.Flags().GetBool("ssh")
 		isSshPathSet := cmd.Flag("ssh-path").Changed
 		isSshEnabled := ssh || isSshPathSet
-		if isSshEnabled {
+		if isSshEnabled || cmd.Flag(tuiutils.KeystoreFlag).Changed {
 			var err error
 			privateKey, err = tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "", "")
 			cobra.CheckErr(err)
@@ -26,25 +26,67 @@ var rootCmd = &cobra.Command{
 
 func main() {
 	generateAddressCmd := cli.GetGenerateAddressCmd()
+	generateSeedCmd := cli.GetGenerateSeedCmd()
 	sendTransactionCmd := cli.GetSendTransactionCmd()
 	getTransactionFeeCmd := cli.GetGetTransactionFeeCmd()
 	createKeychainCmd := cli.GetCreateKeychainCmd()
 	getKeychainCmd := cli.GetKeychainCmd()
 	addServiceToKeychainCmd := cli.GetAddServiceToKeychainCmd()
 	deleteServiceFromKeychainCmd := cli.GetDeleteServiceFromKeychainCmd()
+	signTransactionCmd := cli.GetSignTransactionCmd()
+	broadcastTransactionCmd := cli.GetBroadcastTransactionCmd()
+	decodeTransactionCmd := cli.GetDecodeTransactionCmd()
+	sendBatchCmd := cli.GetSendBatchCmd()
+	createTokenCmd := cli.GetCreateTokenCmd()
+	mintCollectionCmd := cli.GetMintCollectionCmd()
+	getBalanceCmd := cli.GetGetBalanceCmd()
+	getChainCmd := cli.GetGetChainCmd()
+	watchCmd := cli.GetWatchCmd()
+	deployContractCmd := cli.GetDeployContractCmd()
+	callContractCmd := cli.GetCallContractCmd()
+	getContractCmd := cli.GetGetContractCmd()
+	deployWebsiteCmd := cli.GetDeployWebsiteCmd()
+	decryptOwnershipCmd := cli.GetDecryptOwnershipCmd()
+	configCmd := cli.GetConfigCmd()
+	keystoreCmd := cli.GetKeystoreCmd()
+	signerCmd := cli.GetSignerCmd()
 
 	rootCmd.AddCommand(generateAddressCmd)
+	rootCmd.AddCommand(generateSeedCmd)
 	rootCmd.AddCommand(sendTransactionCmd)
 	rootCmd.AddCommand(getTransactionFeeCmd)
 	rootCmd.AddCommand(createKeychainCmd)
 	rootCmd.AddCommand(getKeychainCmd)
 	rootCmd.AddCommand(addServiceToKeychainCmd)
 	rootCmd.AddCommand(deleteServiceFromKeychainCmd)
+	rootCmd.AddCommand(signTransactionCmd)
+	rootCmd.AddCommand(broadcastTransactionCmd)
+	rootCmd.AddCommand(decodeTransactionCmd)
+	rootCmd.AddCommand(sendBatchCmd)
+	rootCmd.AddCommand(createTokenCmd)
+	rootCmd.AddCommand(mintCollectionCmd)
+	rootCmd.AddCommand(getBalanceCmd)
+	rootCmd.AddCommand(getChainCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(deployContractCmd)
+	rootCmd.AddCommand(callContractCmd)
+	rootCmd.AddCommand(getContractCmd)
+	rootCmd.AddCommand(deployWebsiteCmd)
+	rootCmd.AddCommand(decryptOwnershipCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(keystoreCmd)
+	rootCmd.AddCommand(signerCmd)
 
 	rootCmd.Flags().Bool("ssh", false, "Enable SSH key mode")
 	rootCmd.Flags().String("ssh-path", cli.GetFirstSshKeyDefaultPath(), "Path to ssh key")
+	rootCmd.Flags().String(tuiutils.KeystoreFlag, "", "Name of the keystore entry of the seed (see the keystore command)")
+	rootCmd.MarkFlagsMutuallyExclusive(tuiutils.KeystoreFlag, "ssh")
+	rootCmd.MarkFlagsMutuallyExclusive(tuiutils.KeystoreFlag, "ssh-path")
+	cli.SetupGlobalFlags(rootCmd)
+	// the errors are printed in the output format
+	rootCmd.SilenceErrors = true
 
 	err := rootCmd.Execute()
-	cobra.CheckErr(err)
+	cli.CheckError(err)
 
 }
@@ -34,11 +34,13 @@ type Model struct {
 	inputs           []textinput.Model
 	generatedAddress string
 	feedback         string
+	pvKeyBytes       []byte
 }
 
-func New() Model {
+func New(pvKeyBytes []byte) Model {
 	m := Model{
-		inputs: make([]textinput.Model, 4),
+		inputs:     make([]textinput.Model, 4),
+		pvKeyBytes: pvKeyBytes,
 	}
 
 	var t textinput.Model
@@ -50,8 +52,12 @@ func New() Model {
 		case 0:
 			t.Prompt = "> Key generation seed:\n"
 			t.Focus()
-			t.EchoMode = textinput.EchoPassword
-			t.EchoCharacter = '•'
+			if pvKeyBytes != nil {
+				t.Placeholder = "(Imported key)"
+			} else {
+				t.EchoMode = textinput.EchoPassword
+				t.EchoCharacter = '•'
+			}
 		case 1:
 			t.Prompt = "> Index of key to generate\n"
 			t.Placeholder = "(default 0)"
@@ -107,7 +113,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 
 		case "esc":
-			return New(), func() tea.Msg {
+			return New(m.pvKeyBytes), func() tea.Msg {
 				return BackMsg(true)
 			}
 
@@ -118,7 +124,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Did the user press enter while the submit button was focused?
 			// If so, exit.
 			if s == "enter" && m.focusIndex == len(m.inputs) {
-				seed, err := archethic.MaybeConvertToHex(m.inputs[0].Value())
+				seed, err := m.getSeed()
 				if err != nil {
 					m.feedback = err.Error()
 					return m, nil
@@ -191,12 +197,23 @@ func (m *Model) updateInputs(msg tea.Msg) tea.Cmd {
 	cmds := make([]tea.Cmd, len(m.inputs))
 
 	for i := range m.inputs {
+		if m.pvKeyBytes != nil && i == 0 {
+			continue
+		}
 		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
 	}
 
 	return tea.Batch(cmds...)
 }
 
+// getSeed returns the imported key, or the seed of the input
+func (m Model) getSeed() ([]byte, error) {
+	if m.pvKeyBytes != nil {
+		return m.pvKeyBytes, nil
+	}
+	return archethic.MaybeConvertToHex(m.inputs[0].Value())
+}
+
 func (m Model) View() string {
 	var b strings.Builder
 
@@ -12,12 +12,6 @@ import (
 )
 
 var (
-	urlType = []string{"Local", "Testnet", "Mainnet", "Custom"}
-	urls    = map[string]string{
-		"Local":   "http://localhost:4000",
-		"Testnet": "https://testnet.archethic.net",
-		"Mainnet": "https://mainnet.archethic.net",
-		"Custom":  ""}
 	transactionTypesList = []string{
 		"Keychain Access",
 		"Keychain",
@@ -44,6 +38,7 @@ var (
 
 type MainModel struct {
 	mainInputs              []textinput.Model
+	endpoints               tuiutils.NodeEndpoints
 	selectedUrl             string
 	serviceMode             bool
 	serviceName             string
@@ -69,13 +64,15 @@ type UpdateTransactionType struct {
 }
 
 type SendTransaction struct {
-	Curve archethic.Curve
-	Seed  []byte
+	Curve  archethic.Curve
+	Seed   []byte
+	MaxFee string
 }
 
 type GetTransactionFee struct {
-	Curve archethic.Curve
-	Seed  []byte
+	Curve  archethic.Curve
+	Seed   []byte
+	MaxFee string
 }
 
 type ResetInterface struct{}
@@ -96,7 +93,7 @@ func NewMainModel(pvKeyBytes []byte) MainModel {
 		case 1:
 			t.Prompt = "> Access seed\n"
 			if pvKeyBytes != nil {
-				t.Placeholder = "(Imported SSH key)"
+				t.Placeholder = "(Imported key)"
 			} else {
 				t.EchoMode = textinput.EchoPassword
 				t.EchoCharacter = '•'
@@ -110,12 +107,19 @@ func NewMainModel(pvKeyBytes []byte) MainModel {
 			t.Prompt = "> Index\n"
 			t.Placeholder = "(default 0)"
 			t.Validate = numberValidator
-		case 4:
-			t.Prompt = ""
+		case MAX_FEE_INPUT:
+			t.Prompt = "> Max fee (UCO, USD or EUR)\n"
+			t.Placeholder = "(optional, for instance 0.5 or 1USD)"
 		}
 		m.mainInputs[i] = t
 	}
 
+	endpoints, err := tuiutils.LoadNodeEndpoints()
+	m.endpoints = endpoints
+	setFocusIndexes(len(endpoints.Names))
+	if err != nil {
+		m.feedback = err.Error()
+	}
 	return m
 }
 
@@ -168,16 +172,16 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 
 			if m.focusInput < URL_INDEX {
-				u := urlType[m.focusInput]
-				m.mainInputs[0].SetValue(urls[u])
+				u := m.endpoints.Names[m.focusInput]
+				m.mainInputs[0].SetValue(m.endpoints.URLs[u])
 				m.selectedUrl = u
 				m.focusInput = URL_INDEX
 				m, cmds := updateMainFocus(m)
 				cmds = append(cmds, m.updateMainInputs(msg)...)
 				return m, func() tea.Msg {
-					return UpdateUrl{Url: urls[u], cmds: cmds}
+					return UpdateUrl{Url: m.endpoints.URLs[u], cmds: cmds}
 				}
-			} else if m.focusInput > TRANSACTION_INDEX_FIELD_INDEX && m.focusInput < MAIN_ADD_BUTTON_INDEX {
+			} else if m.focusInput > TRANSACTION_INDEX_FIELD_INDEX && m.focusInput < MAX_FEE_FIELD_INDEX {
 				m.selectedTransactionType = transactionTypesList[m.focusInput-FIRST_TRANSACTION_TYPE_INDEX]
 				m.focusInput = MAIN_ADD_BUTTON_INDEX
 				m, cmds := updateMainFocus(m)
@@ -198,7 +202,7 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							return m
 						}
 					}
-					return SendTransaction{Curve: getCurve(&m), Seed: seed}
+					return SendTransaction{Curve: getCurve(&m), Seed: seed, MaxFee: m.mainInputs[MAX_FEE_INPUT].Value()}
 				}
 			} else if m.focusInput == MAIN_RESET_BUTTON_INDEX {
 				return m, func() tea.Msg {
@@ -217,7 +221,7 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							return m
 						}
 					}
-					return GetTransactionFee{Curve: getCurve(&m), Seed: seed}
+					return GetTransactionFee{Curve: getCurve(&m), Seed: seed, MaxFee: m.mainInputs[MAX_FEE_INPUT].Value()}
 				}
 			}
 
@@ -277,11 +281,19 @@ func (m *MainModel) updateMainInputs(msg tea.Msg) []tea.Cmd {
 
 func updateMainFocus(m MainModel) (MainModel, []tea.Cmd) {
 
+	// the node endpoints are not focusable fields,
+	// and the max fee field comes after the transaction types
+	focusedInput := m.focusInput - len(m.endpoints.Names)
+	if m.focusInput > TRANSACTION_INDEX_FIELD_INDEX {
+		focusedInput = -1
+	}
+	if m.focusInput == MAX_FEE_FIELD_INDEX {
+		focusedInput = MAX_FEE_INPUT
+	}
+
 	cmds := make([]tea.Cmd, len(m.mainInputs))
 	for i := 0; i <= len(m.mainInputs)-1; i++ {
-		// the first 4 inputs are not focusable fields (node endpoints for URL)
-
-		if i == m.focusInput-len(urlType) {
+		if i == focusedInput {
 			// Set focused state
 			cmds[i] = m.mainInputs[i].Focus()
 			continue
@@ -325,6 +337,10 @@ func (m MainModel) View() string {
 	// transaction type field
 	b.WriteString("> Transaction type:\n")
 	b.WriteString(transactionTypeView(m))
+	b.WriteString("\n")
+
+	// max fee field
+	b.WriteString(m.mainInputs[MAX_FEE_INPUT].View() + "\n")
 
 	// send transaction button
 	button := &blurredButton
@@ -356,19 +372,19 @@ func (m MainModel) View() string {
 }
 
 // URL part of the main tab
-// looping through the urlType array to display the different options
+// looping through the node endpoints to display the different options
 func urlView(m MainModel) string {
 	s := strings.Builder{}
 
-	for i := 0; i < len(urlType); i++ {
+	for i := 0; i < len(m.endpoints.Names); i++ {
 		var u string
 
-		if m.selectedUrl == urlType[i] {
+		if m.selectedUrl == m.endpoints.Names[i] {
 			u = "(•) "
 		} else {
 			u = "( ) "
 		}
-		u += urlType[i]
+		u += m.endpoints.Names[i]
 
 		if i == m.focusInput {
 			s.WriteString(focusedStyle.Render(u))
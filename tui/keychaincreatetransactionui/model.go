@@ -66,17 +66,34 @@ const (
 	SMART_CONTRACT_TAB createTransactionTab = 6
 )
 
-const (
-	MAIN_ADD_BUTTON_INDEX                 = 17
-	MAIN_GET_TRANSACTION_FEE_BUTTON_INDEX = 18
-	MAIN_RESET_BUTTON_INDEX               = 19
-	FIRST_TRANSACTION_TYPE_INDEX          = 8
-	URL_INDEX                             = 4
-	SEED_INDEX                            = 5
-	CURVE_INDEX                           = 6
-	TRANSACTION_INDEX_FIELD_INDEX         = 7
+// the focus indexes of the main tab, after the node endpoints (whose number depends on the profiles),
+// set by setFocusIndexes when the main model is created
+var (
+	URL_INDEX                             int
+	SEED_INDEX                            int
+	CURVE_INDEX                           int
+	TRANSACTION_INDEX_FIELD_INDEX         int
+	FIRST_TRANSACTION_TYPE_INDEX          int
+	MAX_FEE_FIELD_INDEX                   int
+	MAIN_ADD_BUTTON_INDEX                 int
+	MAIN_GET_TRANSACTION_FEE_BUTTON_INDEX int
+	MAIN_RESET_BUTTON_INDEX               int
 )
 
+func setFocusIndexes(endpointCount int) {
+	URL_INDEX = endpointCount
+	SEED_INDEX = URL_INDEX + 1
+	CURVE_INDEX = URL_INDEX + 2
+	TRANSACTION_INDEX_FIELD_INDEX = URL_INDEX + 3
+	FIRST_TRANSACTION_TYPE_INDEX = URL_INDEX + 4
+	MAX_FEE_FIELD_INDEX = FIRST_TRANSACTION_TYPE_INDEX + len(transactionTypesList)
+	MAIN_ADD_BUTTON_INDEX = MAX_FEE_FIELD_INDEX + 1
+	MAIN_GET_TRANSACTION_FEE_BUTTON_INDEX = MAX_FEE_FIELD_INDEX + 2
+	MAIN_RESET_BUTTON_INDEX = MAX_FEE_FIELD_INDEX + 3
+}
+
+const MAX_FEE_INPUT = 4
+
 type SwitchTab struct{}
 type Model struct {
 	Tabs                   []string
@@ -101,6 +118,7 @@ type Model struct {
 	Spinner                spinner.Model
 	IsInit                 bool
 	pvKeyBytes             []byte
+	autoOwnership          bool
 }
 
 func New(pvKeyBytes []byte) Model {
@@ -131,6 +149,7 @@ func (m *Model) resetInterface(pvKeyBytes []byte) {
 	m.ownershipsModel = NewOwnershipsModel(m.secretKey, &m.transaction)
 	m.contentModel = NewContentModel()
 	m.smartContractModel = NewSmartContractModel()
+	m.autoOwnership = false
 	if m.serviceMode {
 		w, _ := m.mainModel.Update(CreateTransactionMsg{
 			ServiceName: m.serviceName,
@@ -187,7 +206,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case TransactionSent:
 		m.showSpinner = false
 		if msg.Error != nil {
-			m.feedback = msg.Error.Error()
+			m.feedback = tuiutils.DescribeError(msg.Error)
 		} else {
 			m.feedback = msg.Model.feedback
 		}
@@ -195,7 +214,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case TransactionFeeSent:
 		m.showSpinner = false
 		if msg.Error != nil {
-			m.feedback = msg.Error.Error()
+			m.feedback = tuiutils.DescribeError(msg.Error)
 		} else {
 			m.feedback = msg.Model.feedback
 		}
@@ -203,12 +222,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case SendTransaction:
 		m.showSpinner = true
 		return m, func() tea.Msg {
-			return sendTransaction(&m, msg.Curve, msg.Seed)
+			return sendTransaction(&m, msg.Curve, msg.Seed, msg.MaxFee)
 		}
 	case GetTransactionFee:
 		m.showSpinner = true
 		return m, func() tea.Msg {
-			return getTransactionFee(&m, msg.Curve, msg.Seed)
+			return getTransactionFee(&m, msg.Curve, msg.Seed, msg.MaxFee)
 		}
 	case ResetInterface:
 		m.resetInterface(m.pvKeyBytes)
@@ -268,6 +287,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.transaction.SetContent(msg.Content)
 	case UpdateSmartContract:
 		m.transaction.SetCode(msg.Code)
+	case UpdateAutoOwnership:
+		m.autoOwnership = msg.Enabled
 	case tea.KeyMsg:
 		switch keypress := msg.String(); keypress {
 		case "esc":
@@ -497,25 +518,85 @@ func min(a, b int) int {
 	return b
 }
 
-func sendTransaction(m *Model, curve archethic.Curve, seed []byte) TransactionSent {
+func sendTransaction(m *Model, curve archethic.Curve, seed []byte, maxFee string) TransactionSent {
 	m.feedback = ""
-	feedback, error := tuiutils.SendTransaction(&m.transaction, m.secretKey, curve, m.serviceMode, m.url, m.transactionIndex, m.serviceName, m.storageNouncePublicKey, seed)
-	m.feedback = fmt.Sprintf("Transaction sent: %s", feedback)
+	checks, err := getTransactionChecks(maxFee)
+	if err != nil {
+		return TransactionSent{Model: *m, Error: err}
+	}
+	ownership, err := addContractOwnership(m, seed)
+	if err != nil {
+		return TransactionSent{Model: *m, Error: err}
+	}
+	feedback, error := tuiutils.SendTransaction(&m.transaction, m.secretKey, curve, m.serviceMode, m.url, m.transactionIndex, m.serviceName, m.storageNouncePublicKey, tuiutils.SeedSigner(seed), checks, 0, tuiutils.DefaultTimeout)
+	m.feedback = fmt.Sprintf("%sTransaction sent: %s", ownership, feedback)
 	if error != nil {
 		return TransactionSent{Model: *m, Error: error}
 	}
 	return TransactionSent{Model: *m, Error: nil}
 }
 
-func getTransactionFee(m *Model, curve archethic.Curve, seed []byte) TransactionFeeSent {
+func getTransactionFee(m *Model, curve archethic.Curve, seed []byte, maxFee string) TransactionFeeSent {
 	m.feedback = ""
-	fee, error := tuiutils.GetTransactionFee(&m.transaction, m.secretKey, curve, m.serviceMode, m.url, m.transactionIndex, m.serviceName, m.storageNouncePublicKey, seed)
+	checks, err := getTransactionChecks(maxFee)
+	if err != nil {
+		return TransactionFeeSent{Model: *m, Error: err}
+	}
+	ownership, err := addContractOwnership(m, seed)
+	if err != nil {
+		return TransactionFeeSent{Model: *m, Error: err}
+	}
+	fee, error := tuiutils.GetTransactionFee(&m.transaction, m.secretKey, curve, m.serviceMode, m.url, m.transactionIndex, m.serviceName, m.storageNouncePublicKey, tuiutils.SeedSigner(seed))
 	humanReadableFee, _ := strconv.ParseFloat(archethic.FormatBigInt(fee.Fee, 8), 64)
 	usdEquivalent := humanReadableFee * float64(fee.Rates.Usd)
 	eurEquivanlent := humanReadableFee * float64(fee.Rates.Eur)
-	m.feedback = fmt.Sprintf("Transaction fee: %f UCO (~ $%f) (~ %f€)", humanReadableFee, usdEquivalent, eurEquivanlent)
+	m.feedback = fmt.Sprintf("%sTransaction fee: %f UCO (~ $%f) (~ %f€)", ownership, humanReadableFee, usdEquivalent, eurEquivanlent)
 	if error != nil {
 		return TransactionFeeSent{Model: *m, Error: error}
 	}
+	// the checks done before sending the transaction
+	if err := tuiutils.CheckTransactionFee(&m.transaction, m.url, fee, checks); err != nil {
+		m.feedback += "\nThe transaction can't be sent: " + tuiutils.DescribeError(err)
+	} else {
+		m.feedback += "\nFee and balance checks passed"
+	}
 	return TransactionFeeSent{Model: *m, Error: nil}
 }
+
+// getTransactionChecks returns the checks done before sending the transaction: the balance is always checked
+func getTransactionChecks(maxFee string) (tuiutils.TransactionChecks, error) {
+	checks := tuiutils.TransactionChecks{CheckBalance: true}
+	if strings.TrimSpace(maxFee) != "" {
+		parsedMaxFee, err := tuiutils.ParseMaxFee(maxFee)
+		if err != nil {
+			return checks, err
+		}
+		checks.MaxFee = &parsedMaxFee
+	}
+	return checks, nil
+}
+
+// addContractOwnership sets the contract type and adds the contract ownership when it is enabled
+// on the smart contract tab, and returns the description of the ownership
+func addContractOwnership(m *Model, seed []byte) (string, error) {
+	if !m.autoOwnership || len(m.transaction.Data.Code) == 0 {
+		return "", nil
+	}
+	if m.storageNouncePublicKey == "" {
+		storageNouncePublicKey, err := archethic.NewAPIClient(m.url).GetStorageNoncePublicKey()
+		if err != nil {
+			return "", err
+		}
+		m.storageNouncePublicKey = storageNouncePublicKey
+	}
+	chainSeed, err := tuiutils.GetContractChainSeed(m.url, tuiutils.SeedSigner(seed), m.serviceMode, m.serviceName)
+	if err != nil {
+		return "", err
+	}
+	m.transaction.SetType(archethic.ContractType)
+	err = tuiutils.AddContractOwnership(&m.transaction, m.secretKey, m.storageNouncePublicKey, chainSeed)
+	if err != nil {
+		return "", err
+	}
+	return tuiutils.DescribeContractOwnership(&m.transaction, m.secretKey, m.storageNouncePublicKey, chainSeed) + "\n", nil
+}
@@ -14,16 +14,29 @@ var (
 	blurredPasteSmartContractButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Paste"))
 )
 
+const (
+	SMART_CONTRACT_PASTE_INDEX = 1
+	// the auto ownership toggle follows the paste button, if the clipboard is available
+	SMART_CONTRACT_AUTO_OWNERSHIP_INDEX = 2
+)
+
 type SmartContractModel struct {
 	smartContractTextAreaInput textarea.Model
 	focusInput                 int
 	enablePaste                bool
+	autoOwnership              bool
 }
 
 type UpdateSmartContract struct {
 	Code string
 }
 
+// UpdateAutoOwnership enables the automatic ownership of the smart contract,
+// added when the transaction is sent with the contract type
+type UpdateAutoOwnership struct {
+	Enabled bool
+}
+
 func NewSmartContractModel() SmartContractModel {
 	m := SmartContractModel{}
 	m.smartContractTextAreaInput = textarea.New()
@@ -56,15 +69,21 @@ func (m SmartContractModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "up", "down":
-			if !m.smartContractTextAreaInput.Focused() && m.enablePaste {
+			if !m.smartContractTextAreaInput.Focused() {
 				updateSmartContractFocusInput(&m, keypress)
 			} else {
 				return updateSmartContractValue(&m, msg)
 			}
 
 		case "enter":
+			if m.focusInput == m.autoOwnershipIndex() {
+				m.autoOwnership = !m.autoOwnership
+				return m, func() tea.Msg {
+					return UpdateAutoOwnership{Enabled: m.autoOwnership}
+				}
+			}
 			// Paste button
-			if m.focusInput == 1 && m.enablePaste {
+			if m.focusInput == SMART_CONTRACT_PASTE_INDEX && m.enablePaste {
 				if !m.smartContractTextAreaInput.Focused() {
 					m.smartContractTextAreaInput.Focus()
 					m.focusInput = 0
@@ -95,16 +114,23 @@ func updateSmartContractValue(m *SmartContractModel, msg tea.Msg) (SmartContract
 	}
 }
 
+func (m SmartContractModel) autoOwnershipIndex() int {
+	if m.enablePaste {
+		return SMART_CONTRACT_AUTO_OWNERSHIP_INDEX
+	}
+	return SMART_CONTRACT_PASTE_INDEX
+}
+
 func updateSmartContractFocusInput(m *SmartContractModel, keypress string) {
 	if keypress == "up" {
 		m.focusInput--
 	} else {
 		m.focusInput++
 	}
-	if m.focusInput > 1 {
+	if m.focusInput > m.autoOwnershipIndex() {
 		m.focusInput = 0
 	} else if m.focusInput < 0 {
-		m.focusInput = 1
+		m.focusInput = m.autoOwnershipIndex()
 	}
 }
 
@@ -120,11 +146,23 @@ func (m SmartContractModel) View() string {
 		b.WriteString(helpStyle.Render("\npress 'esc' to exit edit mode "))
 	}
 	button := &blurredPasteSmartContractButton
-	if m.focusInput == 1 {
+	if m.focusInput == SMART_CONTRACT_PASTE_INDEX {
 		button = &focusedPasteSmartContractButton
 	}
 	if m.enablePaste {
-		fmt.Fprintf(&b, "\n\n%s\n\n", *button)
+		fmt.Fprintf(&b, "\n\n%s", *button)
+	}
+
+	toggle := "[ ] "
+	if m.autoOwnership {
+		toggle = "[x] "
+	}
+	toggle += "Add the contract ownership automatically"
+	if m.focusInput == m.autoOwnershipIndex() {
+		toggle = focusedStyle.Render(toggle)
 	}
+	fmt.Fprintf(&b, "\n\n%s\n", toggle)
+	b.WriteString(helpStyle.Render("the transaction type is set to Contract, and an ownership with the chain seed as secret and the storage nonce public key as authorized key is added when the transaction is sent"))
+	b.WriteString("\n\n")
 	return b.String()
 }
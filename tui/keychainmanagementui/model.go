@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/archethic-foundation/archethic-cli/tui/keychaincreatetransactionui"
+	"github.com/archethic-foundation/archethic-cli/tui/seedbackupui"
 	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	archethic "github.com/archethic-foundation/libgo"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -49,12 +50,6 @@ var (
 	createTransactionaccessBlurredButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Create Transaction for Service"))
 	createServiceFocusedButton           = focusedStyle.Copy().Render("[ Create Service ]")
 	createServiceBlurredButton           = fmt.Sprintf("[ %s ]", blurredStyle.Render("Create Service"))
-	urlType                              = []string{"Local", "Testnet", "Mainnet", "Custom"}
-	urls                                 = map[string]string{
-		"Local":   "http://localhost:4000",
-		"Testnet": "https://testnet.archethic.net",
-		"Mainnet": "https://mainnet.archethic.net",
-		"Custom":  ""}
 )
 
 type Model struct {
@@ -64,9 +59,12 @@ type Model struct {
 	newServiceInputs                 []textinput.Model
 	keychain                         *archethic.Keychain
 	serviceNames                     []string
+	endpoints                        tuiutils.NodeEndpoints
 	selectedUrl                      string
 	selectedService                  int
 	keychainSeed                     string
+	seedBackup                       seedbackupui.Model
+	seedVerified                     bool
 	keychainTransactionAddress       string
 	keychainAccessTransactionAddress string
 	feedback                         string
@@ -100,7 +98,7 @@ func New(pvKeyBytes []byte) Model {
 		case 1:
 			t.Prompt = "> Access seed\n"
 			if pvKeyBytes != nil {
-				t.Placeholder = "(Imported SSH key)"
+				t.Placeholder = "(Imported key)"
 			} else {
 				t.EchoMode = textinput.EchoPassword
 				t.EchoCharacter = '•'
@@ -124,6 +122,11 @@ func New(pvKeyBytes []byte) Model {
 		m.newServiceInputs[i] = t
 	}
 
+	endpoints, err := tuiutils.LoadNodeEndpoints()
+	m.endpoints = endpoints
+	if err != nil {
+		m.feedback = err.Error()
+	}
 	return m
 }
 
@@ -132,7 +135,7 @@ func (m Model) Init() tea.Cmd {
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	urlBlockSize := len(urlType)
+	urlBlockSize := len(m.endpoints.Names)
 	switch msg := msg.(type) {
 	case SendNewKeychainTransaction:
 		m.feedback = msg.Model.feedback
@@ -140,6 +143,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.keychainTransactionAddress = msg.Model.keychainTransactionAddress
 		m.keychainAccessTransactionAddress = msg.Model.keychainAccessTransactionAddress
 		m.showSpinnerCreate = false
+		m.seedVerified = false
+		if seed, err := hex.DecodeString(m.keychainSeed); err == nil && len(seed) > 0 {
+			// the seed is shown as a mnemonic until it is written down
+			m.seedBackup = seedbackupui.New(seed)
+		}
+		return m, nil
+	case seedbackupui.VerifiedMsg:
+		m.keychainSeed = ""
+		m.seedVerified = true
 		return m, nil
 	case SendAccessKeychain:
 		m.keychain = msg.Model.keychain
@@ -164,6 +176,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds := m.updateInputs(msg)
 		return m, tea.Batch(cmds...)
 	case tea.KeyMsg:
+		if m.keychainSeed != "" && msg.String() != "ctrl+c" && (msg.String() != "esc" || m.seedBackup.Quiz()) {
+			backup, cmd := m.seedBackup.Update(msg)
+			m.seedBackup = backup.(seedbackupui.Model)
+			return m, cmd
+		}
 		switch msg.String() {
 		case "ctrl+c":
 			return m, tea.Quit
@@ -175,8 +192,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "enter":
 			if m.focusIndex < urlBlockSize {
-				u := urlType[m.focusIndex]
-				m.inputs[0].SetValue(urls[u])
+				u := m.endpoints.Names[m.focusIndex]
+				m.inputs[0].SetValue(m.endpoints.URLs[u])
 				m.selectedUrl = u
 				m.focusIndex = urlBlockSize
 			}
@@ -205,6 +222,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.showSpinnerAccess = true
 				m.feedback = ""
 				m.keychainSeed = ""
+				m.seedVerified = false
 				return m, func() tea.Msg {
 					return SendAccessKeychain{accessKeychain(&m)}
 				}
@@ -330,7 +348,7 @@ func accessKeychain(m *Model) Model {
 		return *m
 	}
 
-	keychain, err := tuiutils.AccessKeychain(m.inputs[0].Value(), accessSeed)
+	keychain, err := tuiutils.AccessKeychain(m.inputs[0].Value(), tuiutils.SeedSigner(accessSeed))
 	if err != nil {
 		m.feedback = err.Error()
 		return *m
@@ -408,11 +426,15 @@ func createKeychain(m *Model) Model {
 		m.feedback = err.Error()
 		return *m
 	}
-	feedback, keychainSeed, keychainTransactionAddress, keychainAccessTransactionAddress, error := tuiutils.CreateKeychain(m.inputs[0].Value(), accessSeed)
-	if error != nil {
-		m.feedback = error.Error()
-	} else {
+	feedback, keychainSeed, keychainTransactionAddress, keychainAccessTransactionAddress, error := tuiutils.CreateKeychain(m.inputs[0].Value(), tuiutils.SeedSigner(accessSeed))
+	switch {
+	case error == nil:
 		m.feedback = feedback
+	case keychainSeed != "":
+		// the keychain is created, only its access transaction failed
+		m.feedback = feedback + "\n" + tuiutils.DescribeError(error)
+	default:
+		m.feedback = error.Error()
 	}
 	m.keychainSeed = keychainSeed
 	m.keychainTransactionAddress = keychainTransactionAddress
@@ -421,18 +443,18 @@ func createKeychain(m *Model) Model {
 }
 
 func addServiceToKeychain(m *Model, accessSeed []byte, endpoint string, serviceName string, serviceDerivationPath string) {
-	feedback, err := tuiutils.AddServiceToKeychain(accessSeed, endpoint, serviceName, serviceDerivationPath)
+	feedback, err := tuiutils.AddServiceToKeychain(tuiutils.SeedSigner(accessSeed), endpoint, serviceName, serviceDerivationPath, tuiutils.AllConfirmations, tuiutils.DefaultTimeout)
 	if err != nil {
-		m.feedback = err.Error()
+		m.feedback = tuiutils.DescribeError(err)
 	} else {
 		m.feedback = feedback
 	}
 }
 
 func removeServiceFromKeychain(m *Model, accessSeed []byte, endpoint string, serviceName string) {
-	feedback, err := tuiutils.RemoveServiceFromKeychain(accessSeed, endpoint, serviceName)
+	feedback, err := tuiutils.RemoveServiceFromKeychain(tuiutils.SeedSigner(accessSeed), endpoint, serviceName, tuiutils.AllConfirmations, tuiutils.DefaultTimeout)
 	if err != nil {
-		m.feedback = err.Error()
+		m.feedback = tuiutils.DescribeError(err)
 	} else {
 		m.feedback = feedback
 	}
@@ -454,7 +476,7 @@ func (m Model) View() string {
 	}
 
 	createButton := &createBlurredButton
-	if m.focusIndex == len(m.inputs)+len(urlType) {
+	if m.focusIndex == len(m.inputs)+len(m.endpoints.Names) {
 		createButton = &createFocusedButton
 	}
 
@@ -467,7 +489,12 @@ func (m Model) View() string {
 	fmt.Fprintf(&b, "\n\n%s", *createButton)
 	b.WriteRune('\n')
 	if m.keychainSeed != "" {
-		fmt.Fprintf(&b, "Keychain seed: %s\n", m.keychainSeed)
+		b.WriteString(m.seedBackup.View())
+		b.WriteString("\n\n")
+	} else if m.seedVerified {
+		b.WriteString("Keychain seed: verified, it won't be shown again\n")
+	}
+	if m.keychainSeed != "" || m.seedVerified {
 		fmt.Fprintf(&b, "Keychain transaction: %s\n", m.keychainTransactionAddress)
 		fmt.Fprintf(&b, "Keychain access transaction: %s\n", m.keychainAccessTransactionAddress)
 	}
@@ -558,7 +585,11 @@ func (m Model) View() string {
 	}
 
 	b.WriteString("\n\n")
-	b.WriteString(helpStyle.Render("press 'esc' to go back "))
+	if m.keychainSeed != "" && !m.seedBackup.Quiz() {
+		b.WriteString(helpStyle.Render("press 'esc' to go back, the keychain seed won't be shown again "))
+	} else {
+		b.WriteString(helpStyle.Render("press 'esc' to go back "))
+	}
 
 	return b.String()
 }
@@ -566,14 +597,14 @@ func (m Model) View() string {
 func urlView(m Model) string {
 	s := strings.Builder{}
 
-	for i := 0; i < len(urlType); i++ {
+	for i := 0; i < len(m.endpoints.Names); i++ {
 		var u string
-		if m.selectedUrl == urlType[i] {
+		if m.selectedUrl == m.endpoints.Names[i] {
 			u = "(•) "
 		} else {
 			u = "( ) "
 		}
-		u += urlType[i]
+		u += m.endpoints.Names[i]
 		if i == m.focusIndex {
 			s.WriteString(focusedStyle.Render(u))
 		} else {
@@ -586,15 +617,8 @@ func urlView(m Model) string {
 }
 
 func getAccessKey(m Model) ([]byte, error) {
-	potentialWordsList := strings.Fields(m.inputs[1].Value())
-	if len(potentialWordsList) == 24 {
-		seed, err := tuiutils.ExtractSeedFromMnemonic(m.inputs[1].Value())
-		if err != nil {
-			return nil, err
-		}
-		if seed != nil {
-			return seed, nil
-		}
+	if m.pvKeyBytes == nil && tuiutils.LooksLikeMnemonic(m.inputs[1].Value()) {
+		return tuiutils.ExtractSeedFromMnemonic(m.inputs[1].Value(), tuiutils.MnemonicOptions{})
 	}
 
 	accessSeed, err := archethic.MaybeConvertToHex(m.inputs[1].Value())
@@ -0,0 +1,20 @@
+package keystoreui
+
+import (
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func selectCmd(seed []byte) tea.Cmd {
+	return func() tea.Msg {
+		return SelectMsg{Seed: seed}
+	}
+}
+
+// decryptCmd decrypts the seed of the entry, out of the update loop as the key derivation takes a while
+func decryptCmd(name string, passphrase string) tea.Cmd {
+	return func() tea.Msg {
+		seed, err := tuiutils.LoadKeystoreSeed(name, []byte(passphrase))
+		return decryptedMsg{seed: seed, err: err}
+	}
+}
@@ -0,0 +1,11 @@
+package keystoreui
+
+// SelectMsg is sent when the seed is picked, the seed is nil when the seeds are typed in the forms
+type SelectMsg struct {
+	Seed []byte
+}
+
+type decryptedMsg struct {
+	seed []byte
+	err  error
+}
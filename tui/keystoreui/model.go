@@ -0,0 +1,144 @@
+package keystoreui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	focusedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	blurredStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	cursorStyle  = focusedStyle.Copy()
+	helpStyle    = blurredStyle.Copy()
+)
+
+// Model picks the seed of the keystore used by the forms, in place of their seed input
+type Model struct {
+	entries    []tuiutils.KeystoreEntry
+	focusIndex int
+	// selected is the index of the entry whose passphrase is asked, -1 while picking the entry
+	selected   int
+	passphrase textinput.Model
+	feedback   string
+}
+
+// New returns the picker of the keystore entries, the last choice is to type the seeds in the forms
+func New(entries []tuiutils.KeystoreEntry) Model {
+	t := textinput.New()
+	t.CursorStyle = cursorStyle
+	t.Prompt = "> Passphrase\n"
+	t.EchoMode = textinput.EchoPassword
+	t.EchoCharacter = '•'
+	return Model{
+		entries:    entries,
+		selected:   -1,
+		passphrase: t,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case decryptedMsg:
+		if msg.err != nil {
+			m.feedback = tuiutils.DescribeError(msg.err)
+			m.passphrase.SetValue("")
+			return m, nil
+		}
+		return m, selectCmd(msg.seed)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			if m.selected >= 0 {
+				// back to the entries
+				m.selected = -1
+				m.feedback = ""
+				m.passphrase.SetValue("")
+				m.passphrase.Blur()
+			}
+			return m, nil
+		case "up", "shift+tab":
+			if m.selected < 0 && m.focusIndex > 0 {
+				m.focusIndex--
+			}
+			return m, nil
+		case "down", "tab":
+			if m.selected < 0 && m.focusIndex < len(m.entries) {
+				m.focusIndex++
+			}
+			return m, nil
+		case "enter":
+			if m.selected >= 0 {
+				m.feedback = "Decrypting..."
+				return m, decryptCmd(m.entries[m.selected].Name, m.passphrase.Value())
+			}
+			if m.focusIndex == len(m.entries) {
+				return m, selectCmd(nil)
+			}
+			m.selected = m.focusIndex
+			m.feedback = ""
+			return m, m.passphrase.Focus()
+		}
+	}
+
+	if m.selected < 0 {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.passphrase, cmd = m.passphrase.Update(msg)
+	return m, cmd
+}
+
+func (m Model) View() string {
+	var b strings.Builder
+
+	b.WriteString("> Seed of the keystore:\n")
+	for i, entry := range m.entries {
+		choice := "( ) "
+		if i == m.selected {
+			choice = "(•) "
+		}
+		choice += fmt.Sprintf("%s (%s)", entry.Name, entry.Kind)
+		if i == m.focusIndex {
+			b.WriteString(focusedStyle.Render(choice))
+		} else {
+			b.WriteString(choice)
+		}
+		b.WriteRune('\n')
+	}
+	choice := "( ) Type the seeds in the forms"
+	if m.focusIndex == len(m.entries) {
+		b.WriteString(focusedStyle.Render(choice))
+	} else {
+		b.WriteString(choice)
+	}
+	b.WriteRune('\n')
+
+	if m.selected >= 0 {
+		b.WriteRune('\n')
+		b.WriteString(m.passphrase.View())
+		b.WriteRune('\n')
+	}
+
+	if m.feedback != "" {
+		b.WriteString("\n" + m.feedback + "\n")
+	}
+
+	b.WriteString("\n")
+	if m.selected >= 0 {
+		b.WriteString(helpStyle.Render("press 'enter' to decrypt the seed, 'esc' to pick another entry"))
+	} else {
+		b.WriteString(helpStyle.Render("press 'enter' to select"))
+	}
+	return b.String()
+}
@@ -74,6 +74,7 @@ func menu() []list.Item {
 		item{title: "Generate Address", desc: ""},
 		item{title: "Build & Send Transaction", desc: ""},
 		item{title: "Keychain Management", desc: ""},
+		item{title: "Transaction History", desc: ""},
 	}
 	return menu
 }
@@ -0,0 +1,9 @@
+package seedbackupui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+func verifiedCmd() tea.Cmd {
+	return func() tea.Msg {
+		return VerifiedMsg{}
+	}
+}
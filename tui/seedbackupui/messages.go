@@ -0,0 +1,4 @@
+package seedbackupui
+
+// VerifiedMsg is sent when the words asked back match the mnemonic of the seed
+type VerifiedMsg struct{}
@@ -0,0 +1,151 @@
+package seedbackupui
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// quizWords is the number of words asked back before the seed is considered written down
+const quizWords = 3
+
+var (
+	focusedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	blurredStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	cursorStyle  = focusedStyle.Copy()
+	helpStyle    = blurredStyle.Copy()
+)
+
+// Model shows a seed as a mnemonic until it is written down, then asks random words of it back
+type Model struct {
+	seed     string
+	mnemonic string
+	// positions are the positions of the words asked back, empty while the seed is shown
+	positions []int
+	answered  int
+	word      textinput.Model
+	feedback  string
+}
+
+// New returns the backup of the seed, starting by showing it
+func New(seed []byte) Model {
+	t := textinput.New()
+	t.CursorStyle = cursorStyle
+	t.EchoMode = textinput.EchoPassword
+	t.EchoCharacter = '•'
+	m := Model{
+		seed: hex.EncodeToString(seed),
+		word: t,
+	}
+	mnemonic, err := tuiutils.NewMnemonic(seed, tuiutils.DefaultMnemonicLanguage)
+	if err != nil {
+		m.feedback = err.Error()
+	}
+	m.mnemonic = mnemonic
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Quiz reports whether the words are asked back, the seed being hidden
+func (m Model) Quiz() bool {
+	return len(m.positions) > 0
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "esc":
+		if m.Quiz() {
+			// back to the seed
+			m.positions = nil
+			m.feedback = ""
+			m.word.SetValue("")
+			m.word.Blur()
+		}
+		return m, nil
+	case "enter":
+		if !m.Quiz() {
+			if m.mnemonic == "" {
+				return m, nil
+			}
+			positions, err := tuiutils.MnemonicQuiz(m.mnemonic, quizWords)
+			if err != nil {
+				m.feedback = err.Error()
+				return m, nil
+			}
+			m.positions = positions
+			m.answered = 0
+			m.feedback = ""
+			m.word.Prompt = fmt.Sprintf("> Word #%d\n", m.positions[0])
+			return m, m.word.Focus()
+		}
+		position := m.positions[m.answered]
+		word := m.word.Value()
+		m.word.SetValue("")
+		if !tuiutils.MnemonicWordMatches(m.mnemonic, position, word) {
+			// the words are shown again, to be checked
+			m.positions = nil
+			m.word.Blur()
+			m.feedback = fmt.Sprintf("The word #%d doesn't match, check the words written down", position)
+			return m, nil
+		}
+		m.answered++
+		if m.answered == len(m.positions) {
+			m.positions = nil
+			m.word.Blur()
+			return m, verifiedCmd()
+		}
+		m.word.Prompt = fmt.Sprintf("> Word #%d\n", m.positions[m.answered])
+		return m, nil
+	}
+
+	if !m.Quiz() {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.word, cmd = m.word.Update(msg)
+	return m, cmd
+}
+
+func (m Model) View() string {
+	var b strings.Builder
+
+	if m.Quiz() {
+		fmt.Fprintf(&b, "Verify the keychain seed (%d/%d):\n\n", m.answered+1, len(m.positions))
+		b.WriteString(m.word.View())
+		b.WriteRune('\n')
+	} else {
+		b.WriteString("Keychain seed, write down the words and keep them safe:\n\n")
+		words := strings.Fields(m.mnemonic)
+		for i, word := range words {
+			fmt.Fprintf(&b, "%4d. %-10s", i+1, word)
+			if i%6 == 5 || i == len(words)-1 {
+				b.WriteRune('\n')
+			}
+		}
+		fmt.Fprintf(&b, "\nHexadecimal: %s\n", m.seed)
+	}
+
+	if m.feedback != "" {
+		b.WriteString("\n" + m.feedback + "\n")
+	}
+
+	b.WriteString("\n")
+	if m.Quiz() {
+		b.WriteString(helpStyle.Render("press 'enter' to check the word, 'esc' to show the seed again"))
+	} else {
+		b.WriteString(helpStyle.Render("press 'enter' once the words are written down, to verify them"))
+	}
+	return b.String()
+}
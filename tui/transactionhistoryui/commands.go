@@ -0,0 +1,33 @@
+package transactionhistoryui
+
+import (
+	"encoding/hex"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// loadChainCmd fetches all the transactions of the chain of the address, or of the seed if there is no address
+func loadChainCmd(url string, address string, seed []byte) tea.Cmd {
+	return func() tea.Msg {
+		var lastAddress string
+		var err error
+		if address != "" {
+			lastAddress, err = tuiutils.GetLastTransactionAddress(url, address)
+		} else {
+			var index uint
+			index, err = tuiutils.GetLastTransactionIndex(url, archethic.ED25519, seed)
+			if err == nil {
+				var addressBytes []byte
+				addressBytes, err = archethic.DeriveAddress(seed, uint32(index), archethic.ED25519, archethic.SHA256)
+				lastAddress = hex.EncodeToString(addressBytes)
+			}
+		}
+		if err != nil {
+			return ChainLoadedMsg{err: err}
+		}
+		transactions, _, err := tuiutils.GetTransactionChain(url, lastAddress, 0, 0)
+		return ChainLoadedMsg{transactions: transactions, err: err}
+	}
+}
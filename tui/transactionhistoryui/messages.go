@@ -0,0 +1,12 @@
+package transactionhistoryui
+
+import "github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+
+// BackMsg change state back to project view
+type BackMsg bool
+
+// ChainLoadedMsg is sent when the transactions of the chain are fetched
+type ChainLoadedMsg struct {
+	transactions []tuiutils.ChainTransaction
+	err          error
+}
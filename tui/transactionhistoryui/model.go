@@ -0,0 +1,381 @@
+package transactionhistoryui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	focusedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	blurredStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	cursorStyle  = focusedStyle.Copy()
+	noStyle      = lipgloss.NewStyle()
+	helpStyle    = blurredStyle.Copy()
+
+	loadFocusedButton = focusedStyle.Copy().Render("[ Load History ]")
+	loadBlurredButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Load History"))
+
+	detailBoxStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#874BFD")).
+			Padding(0, 1)
+)
+
+const (
+	URL_INPUT = iota
+	ADDRESS_INPUT
+	SEED_INPUT
+)
+
+type Model struct {
+	IsInit       bool
+	focusIndex   int
+	inputs       []textinput.Model
+	endpoints    tuiutils.NodeEndpoints
+	selectedUrl  string
+	transactions []tuiutils.ChainTransaction
+	table        table.Model
+	feedback     string
+	showSpinner  bool
+	Spinner      spinner.Model
+	pvKeyBytes   []byte
+}
+
+func New(pvKeyBytes []byte) Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	m := Model{
+		inputs:     make([]textinput.Model, 3),
+		Spinner:    s,
+		pvKeyBytes: pvKeyBytes,
+		table: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "#", Width: 5},
+				{Title: "Type", Width: 12},
+				{Title: "Timestamp", Width: 20},
+				{Title: "In", Width: 4},
+				{Title: "Out", Width: 4},
+				{Title: "Fee (UCO)", Width: 12},
+				{Title: "Status", Width: 32},
+			}),
+			table.WithHeight(10),
+		),
+	}
+
+	var t textinput.Model
+	for i := range m.inputs {
+		t = textinput.New()
+		t.CursorStyle = cursorStyle
+
+		switch i {
+		case URL_INPUT:
+			t.Prompt = ""
+		case ADDRESS_INPUT:
+			t.Prompt = "> Address of the chain\n"
+		case SEED_INPUT:
+			t.Prompt = "> Or seed of the chain\n"
+			if pvKeyBytes != nil {
+				t.Placeholder = "(Imported key)"
+			} else {
+				t.EchoMode = textinput.EchoPassword
+				t.EchoCharacter = '•'
+			}
+		}
+
+		m.inputs[i] = t
+	}
+
+	endpoints, err := tuiutils.LoadNodeEndpoints()
+	m.endpoints = endpoints
+	if err != nil {
+		m.feedback = err.Error()
+	}
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.Spinner.Tick
+}
+
+// focus indexes: the urls, the inputs, the load button, then the table
+func (m Model) loadButtonIndex() int {
+	return len(m.endpoints.Names) + len(m.inputs)
+}
+
+func (m Model) tableIndex() int {
+	return m.loadButtonIndex() + 1
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ChainLoadedMsg:
+		m.showSpinner = false
+		if msg.err != nil {
+			m.feedback = msg.err.Error()
+			return m, nil
+		}
+		m.feedback = ""
+		m.transactions = msg.transactions
+		m.table.SetRows(transactionRows(m.transactions))
+		m.table.GotoTop()
+		if len(m.transactions) == 0 {
+			m.feedback = "No transaction on this chain"
+			return m, nil
+		}
+		m.focusIndex = m.tableIndex()
+		return m, tea.Batch(m.updateFocus()...)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+
+		case "esc":
+			return New(m.pvKeyBytes), func() tea.Msg {
+				return BackMsg(true)
+			}
+
+		case "enter":
+			if m.focusIndex < len(m.endpoints.Names) {
+				u := m.endpoints.Names[m.focusIndex]
+				m.inputs[URL_INPUT].SetValue(m.endpoints.URLs[u])
+				m.selectedUrl = u
+				m.focusIndex = len(m.endpoints.Names)
+				return m, tea.Batch(m.updateFocus()...)
+			}
+
+			if m.focusIndex == m.loadButtonIndex() {
+				seed, err := m.getSeed()
+				if err != nil {
+					m.feedback = err.Error()
+					return m, nil
+				}
+				url := m.inputs[URL_INPUT].Value()
+				address := strings.TrimSpace(m.inputs[ADDRESS_INPUT].Value())
+				if url == "" {
+					m.feedback = "please select a node endpoint"
+					return m, nil
+				}
+				if address == "" && len(seed) == 0 {
+					m.feedback = "please enter an address or a seed"
+					return m, nil
+				}
+				m.feedback = ""
+				m.showSpinner = true
+				return m, loadChainCmd(url, address, seed)
+			}
+
+		case "up", "down":
+			if m.focusIndex == m.tableIndex() {
+				var cmd tea.Cmd
+				m.table, cmd = m.table.Update(msg)
+				return m, cmd
+			}
+			return m.cycleFocus(msg.String() == "up")
+
+		case "tab", "shift+tab":
+			return m.cycleFocus(msg.String() == "shift+tab")
+
+		default:
+			if m.focusIndex == m.tableIndex() {
+				var cmd tea.Cmd
+				m.table, cmd = m.table.Update(msg)
+				return m, cmd
+			}
+		}
+	default:
+		var cmd tea.Cmd
+		m.Spinner, cmd = m.Spinner.Update(msg)
+		return m, cmd
+	}
+
+	// Handle character input
+	cmds := m.updateInputs(msg)
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) cycleFocus(backward bool) (tea.Model, tea.Cmd) {
+	if backward {
+		m.focusIndex--
+	} else {
+		m.focusIndex++
+	}
+
+	lastIndex := m.loadButtonIndex()
+	if len(m.transactions) > 0 {
+		lastIndex = m.tableIndex()
+	}
+	if m.focusIndex > lastIndex {
+		m.focusIndex = 0
+	} else if m.focusIndex < 0 {
+		m.focusIndex = lastIndex
+	}
+	return m, tea.Batch(m.updateFocus()...)
+}
+
+func (m *Model) updateInputs(msg tea.Msg) []tea.Cmd {
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		if m.pvKeyBytes != nil && i == SEED_INPUT {
+			continue
+		}
+		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+	}
+	return cmds
+}
+
+func (m *Model) updateFocus() []tea.Cmd {
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		if i == m.focusIndex-len(m.endpoints.Names) {
+			// Set focused state
+			cmds[i] = m.inputs[i].Focus()
+			continue
+		}
+		// Remove focused state
+		m.inputs[i].Blur()
+		m.inputs[i].PromptStyle = noStyle
+		m.inputs[i].TextStyle = noStyle
+	}
+	if m.focusIndex == m.tableIndex() {
+		m.table.Focus()
+	} else {
+		m.table.Blur()
+	}
+	return cmds
+}
+
+func (m Model) getSeed() ([]byte, error) {
+	if m.pvKeyBytes != nil {
+		return m.pvKeyBytes, nil
+	}
+	value := strings.TrimSpace(m.inputs[SEED_INPUT].Value())
+	if value == "" {
+		return nil, nil
+	}
+	if tuiutils.LooksLikeMnemonic(value) {
+		return tuiutils.ExtractSeedFromMnemonic(value, tuiutils.MnemonicOptions{})
+	}
+	return archethic.MaybeConvertToHex(value)
+}
+
+func transactionRows(transactions []tuiutils.ChainTransaction) []table.Row {
+	rows := make([]table.Row, len(transactions))
+	for i, tx := range transactions {
+		rows[i] = table.Row{
+			strconv.FormatUint(uint64(tx.Index), 10),
+			tx.Type,
+			formatTimestamp(tx),
+			strconv.Itoa(len(tx.TransfersIn)),
+			strconv.Itoa(len(tx.TransfersOut)),
+			archethic.FormatBigInt(tx.Fee, 8),
+			tx.ValidationStatus(),
+		}
+	}
+	return rows
+}
+
+func formatTimestamp(tx tuiutils.ChainTransaction) string {
+	if !tx.Validated {
+		return ""
+	}
+	return tx.Timestamp.Format(time.DateTime)
+}
+
+func (m Model) View() string {
+	var b strings.Builder
+
+	b.WriteString("> Node endpoint:\n")
+	b.WriteString(urlView(m))
+	for i := range m.inputs {
+		b.WriteRune('\n')
+		b.WriteString(m.inputs[i].View())
+	}
+
+	button := &loadBlurredButton
+	if m.focusIndex == m.loadButtonIndex() {
+		button = &loadFocusedButton
+	}
+	fmt.Fprintf(&b, "\n\n%s\n\n", *button)
+
+	if m.showSpinner {
+		b.WriteString(m.Spinner.View())
+		b.WriteString("\n\n")
+	}
+
+	if m.feedback != "" {
+		b.WriteString(m.feedback)
+		b.WriteString("\n\n")
+	}
+
+	if len(m.transactions) > 0 {
+		b.WriteString(m.table.View())
+		b.WriteString("\n")
+		cursor := m.table.Cursor()
+		if cursor >= 0 && cursor < len(m.transactions) {
+			b.WriteString(detailBoxStyle.Render(transactionDetail(m.transactions[cursor])))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("press 'tab' to move to the history, 'up'/'down' to select a transaction, 'esc' to go back "))
+
+	return b.String()
+}
+
+func transactionDetail(tx tuiutils.ChainTransaction) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Transaction #%d (%s)\n", tx.Index, tx.Type)
+	fmt.Fprintf(&b, "Address: %s\n", tx.Address)
+	fmt.Fprintf(&b, "Timestamp: %s\n", formatTimestamp(tx))
+	fmt.Fprintf(&b, "Status: %s\n", tx.ValidationStatus())
+	fmt.Fprintf(&b, "Fee: %s UCO\n", archethic.FormatBigInt(tx.Fee, 8))
+	b.WriteString("Transfers in:")
+	if len(tx.TransfersIn) == 0 {
+		b.WriteString(" none")
+	}
+	for _, transfer := range tx.TransfersIn {
+		fmt.Fprintf(&b, "\n  %s from %s", tuiutils.FormatTransfer(transfer), transfer.Address)
+	}
+	b.WriteString("\nTransfers out:")
+	if len(tx.TransfersOut) == 0 {
+		b.WriteString(" none")
+	}
+	for _, transfer := range tx.TransfersOut {
+		fmt.Fprintf(&b, "\n  %s to %s", tuiutils.FormatTransfer(transfer), transfer.Address)
+	}
+	return b.String()
+}
+
+func urlView(m Model) string {
+	s := strings.Builder{}
+
+	for i := 0; i < len(m.endpoints.Names); i++ {
+		var u string
+		if m.selectedUrl == m.endpoints.Names[i] {
+			u = "(•) "
+		} else {
+			u = "( ) "
+		}
+		u += m.endpoints.Names[i]
+		if i == m.focusIndex {
+			s.WriteString(focusedStyle.Render(u))
+		} else {
+			s.WriteString(u)
+		}
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
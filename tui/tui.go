@@ -5,10 +5,14 @@ import (
 	"log"
 	"os"
 
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
 	"github.com/archethic-foundation/archethic-cli/tui/generateaddressui"
 	"github.com/archethic-foundation/archethic-cli/tui/keychaincreatetransactionui"
 	"github.com/archethic-foundation/archethic-cli/tui/keychainmanagementui"
+	"github.com/archethic-foundation/archethic-cli/tui/keystoreui"
 	"github.com/archethic-foundation/archethic-cli/tui/mainui"
+	"github.com/archethic-foundation/archethic-cli/tui/transactionhistoryui"
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -21,8 +25,10 @@ const (
 	generateAddressView
 	keychainManagementView
 	keychainCreateTransactionView
+	transactionHistoryView
 	monthView
 	loadingView
+	keystoreView
 )
 
 type MainModel struct {
@@ -31,6 +37,8 @@ type MainModel struct {
 	generateAddress           tea.Model
 	keychainManagement        tea.Model
 	keychainCreateTransaction tea.Model
+	transactionHistory        tea.Model
+	keystore                  tea.Model
 	ActiveMenuID              uint
 	windowSize                tea.WindowSizeMsg
 }
@@ -59,13 +67,29 @@ func StartTea(pvKeyBytes []byte) {
 
 // New initialize the main model for your program
 func New(pvKeyBytes []byte) MainModel {
-	return MainModel{
-		state:                     sessionState(0),
-		main:                      mainui.New(),
-		generateAddress:           generateaddressui.New(),
-		keychainManagement:        keychainmanagementui.New(pvKeyBytes),
-		keychainCreateTransaction: keychaincreatetransactionui.New(pvKeyBytes),
+	m := MainModel{
+		state: sessionState(0),
+		main:  mainui.New(),
 	}
+	m.setKey(pvKeyBytes)
+	if pvKeyBytes == nil {
+		// pick a seed of the keystore, in place of the seed inputs of the forms
+		// (a broken keystore is reported by the commands, the TUI only proposes the seed inputs)
+		entries, _ := tuiutils.ListKeystore()
+		if len(entries) > 0 {
+			m.keystore = keystoreui.New(entries)
+			m.state = keystoreView
+		}
+	}
+	return m
+}
+
+// setKey creates the forms with the imported key, or with their seed inputs if the key is nil
+func (m *MainModel) setKey(pvKeyBytes []byte) {
+	m.generateAddress = generateaddressui.New(pvKeyBytes)
+	m.keychainManagement = keychainmanagementui.New(pvKeyBytes)
+	m.keychainCreateTransaction = keychaincreatetransactionui.New(pvKeyBytes)
+	m.transactionHistory = transactionhistoryui.New(pvKeyBytes)
 }
 
 // Init run any intial IO on program start
@@ -91,8 +115,16 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.state = menuView
 	case keychaincreatetransactionui.BackMsg:
 		m.state = menuView
+	case transactionhistoryui.BackMsg:
+		m.state = menuView
 	case keychaincreatetransactionui.CreateTransactionMsg:
 		m.state = keychainCreateTransactionView
+	case keystoreui.SelectMsg:
+		if msg.Seed != nil {
+			m.setKey(msg.Seed)
+		}
+		m.state = menuView
+		return m, nil
 	case mainui.SelectMsg:
 		switch msg.ActiveMenu {
 		case 1:
@@ -101,6 +133,8 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.state = keychainCreateTransactionView
 		case 3:
 			m.state = keychainManagementView
+		case 4:
+			m.state = transactionHistoryView
 		}
 	}
 
@@ -145,6 +179,26 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.keychainCreateTransaction = newModel
 		cmd = newCmd
+	case keystoreView:
+		newKeystore, newCmd := m.keystore.Update(msg)
+		newModel, ok := newKeystore.(keystoreui.Model)
+		if !ok {
+			panic("could not perform assertion on keystoreui model")
+		}
+		m.keystore = newModel
+		cmd = newCmd
+	case transactionHistoryView:
+		newTransactionHistory, newCmd := m.transactionHistory.Update(msg)
+		newModel, ok := newTransactionHistory.(transactionhistoryui.Model)
+		if !ok {
+			panic("could not perform assertion on transactionhistoryui model")
+		}
+		if !newModel.IsInit {
+			cmds = append(cmds, newModel.Init())
+			newModel.IsInit = true
+		}
+		m.transactionHistory = newModel
+		cmd = newCmd
 	}
 	cmds = append(cmds, cmd)
 	return m, tea.Batch(cmds...)
@@ -161,6 +215,10 @@ func (m MainModel) View() string {
 		return m.keychainManagement.View()
 	case keychainCreateTransactionView:
 		return m.keychainCreateTransaction.View()
+	case transactionHistoryView:
+		return m.transactionHistory.View()
+	case keystoreView:
+		return constants.DocStyle.Render(m.keystore.View())
 	default:
 		return m.main.View()
 	}
@@ -0,0 +1,257 @@
+package tuiutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// chainPageSize is the number of transactions returned by the node for each page of a chain
+const chainPageSize = 10
+
+// ChainTransaction is a validated transaction of a chain
+type ChainTransaction struct {
+	Address            string
+	Index              uint
+	Type               string
+	Timestamp          time.Time
+	Fee                *big.Int
+	Validated          bool
+	NbCrossValidations int
+	TransfersIn        []ChainTransfer
+	TransfersOut       []ChainTransfer
+}
+
+// ChainTransfer is an incoming (from the address) or outgoing (to the address) UCO or token transfer
+type ChainTransfer struct {
+	Address      string
+	Amount       *big.Int
+	TokenAddress string
+	TokenId      uint
+}
+
+// ValidationStatus describes the validation of the transaction
+func (t ChainTransaction) ValidationStatus() string {
+	if !t.Validated {
+		return "pending"
+	}
+	return fmt.Sprintf("validated (%d cross validations)", t.NbCrossValidations)
+}
+
+type transferGQL struct {
+	To           string      `json:"to"`
+	Amount       json.Number `json:"amount"`
+	TokenAddress string      `json:"tokenAddress"`
+	TokenId      uint        `json:"tokenId"`
+}
+
+type inputGQL struct {
+	From         string      `json:"from"`
+	Amount       json.Number `json:"amount"`
+	Type         string      `json:"type"`
+	TokenAddress string      `json:"tokenAddress"`
+	TokenId      uint        `json:"tokenId"`
+}
+
+type chainTransactionGQL struct {
+	Address     string `json:"address"`
+	ChainLength uint   `json:"chainLength"`
+	Type        string `json:"type"`
+	Data        struct {
+		Ledger struct {
+			Uco struct {
+				Transfers []transferGQL `json:"transfers"`
+			} `json:"uco"`
+			Token struct {
+				Transfers []transferGQL `json:"transfers"`
+			} `json:"token"`
+		} `json:"ledger"`
+	} `json:"data"`
+	ValidationStamp *struct {
+		Timestamp        int64 `json:"timestamp"`
+		LedgerOperations struct {
+			Fee json.Number `json:"fee"`
+		} `json:"ledgerOperations"`
+	} `json:"validationStamp"`
+	CrossValidationStamps []struct {
+		NodePublicKey string `json:"nodePublicKey"`
+	} `json:"crossValidationStamps"`
+	Inputs []inputGQL `json:"inputs"`
+}
+
+const transactionChainQuery = `query($address: Address!, $pagingAddress: Address) {
+  transactionChain(address: $address, pagingAddress: $pagingAddress) {
+    address
+    chainLength
+    type
+    data {
+      ledger {
+        uco { transfers { to amount } }
+        token { transfers { to amount tokenAddress tokenId } }
+      }
+    }
+    validationStamp { timestamp ledgerOperations { fee } }
+    crossValidationStamps { nodePublicKey }
+    inputs { from amount type tokenAddress tokenId }
+  }
+}`
+
+// GetTransactionChain returns the transactions of the chain of the address, from the oldest to the newest,
+// skipping the offset first ones. If limit is 0, all the remaining transactions are returned.
+// It also returns true if there are more transactions after the returned ones.
+func GetTransactionChain(endpoint string, address string, offset uint, limit uint) ([]ChainTransaction, bool, error) {
+	var transactions []ChainTransaction
+	var pagingAddress interface{}
+	// the node pages the chain by address, so the pages before the offset are fetched too
+	skipped := uint(0)
+	for {
+		var result struct {
+			TransactionChain []chainTransactionGQL `json:"transactionChain"`
+		}
+		variables := map[string]interface{}{"address": address, "pagingAddress": pagingAddress}
+		if err := queryGraphql(endpoint, transactionChainQuery, variables, &result); err != nil {
+			return nil, false, err
+		}
+		page := result.TransactionChain
+
+		for _, tx := range page {
+			if skipped < offset {
+				skipped++
+				continue
+			}
+			if limit > 0 && uint(len(transactions)) == limit {
+				return transactions, true, nil
+			}
+			chainTransaction, err := newChainTransaction(tx)
+			if err != nil {
+				return nil, false, err
+			}
+			transactions = append(transactions, chainTransaction)
+		}
+
+		if len(page) < chainPageSize {
+			return transactions, false, nil
+		}
+		pagingAddress = page[len(page)-1].Address
+	}
+}
+
+func newChainTransaction(tx chainTransactionGQL) (ChainTransaction, error) {
+	chainTransaction := ChainTransaction{
+		Address:            strings.ToUpper(tx.Address),
+		Type:               tx.Type,
+		Fee:                big.NewInt(0),
+		NbCrossValidations: len(tx.CrossValidationStamps),
+	}
+	if tx.ChainLength > 0 {
+		chainTransaction.Index = tx.ChainLength - 1
+	}
+	if tx.ValidationStamp != nil {
+		chainTransaction.Validated = true
+		chainTransaction.Timestamp = time.Unix(tx.ValidationStamp.Timestamp, 0).UTC()
+		if _, ok := chainTransaction.Fee.SetString(tx.ValidationStamp.LedgerOperations.Fee.String(), 10); !ok {
+			return ChainTransaction{}, fmt.Errorf("invalid fee %q", tx.ValidationStamp.LedgerOperations.Fee)
+		}
+	}
+
+	for _, transfer := range append(tx.Data.Ledger.Uco.Transfers, tx.Data.Ledger.Token.Transfers...) {
+		amount, ok := new(big.Int).SetString(transfer.Amount.String(), 10)
+		if !ok {
+			return ChainTransaction{}, fmt.Errorf("invalid amount %q", transfer.Amount)
+		}
+		chainTransaction.TransfersOut = append(chainTransaction.TransfersOut, ChainTransfer{
+			Address:      strings.ToUpper(transfer.To),
+			Amount:       amount,
+			TokenAddress: strings.ToUpper(transfer.TokenAddress),
+			TokenId:      transfer.TokenId,
+		})
+	}
+
+	for _, input := range tx.Inputs {
+		if !input.isTransfer() {
+			continue
+		}
+		transfer, err := input.toTransfer()
+		if err != nil {
+			return ChainTransaction{}, err
+		}
+		chainTransaction.TransfersIn = append(chainTransaction.TransfersIn, transfer)
+	}
+	return chainTransaction, nil
+}
+
+// isTransfer returns false for the smart contract calls, which are inputs without any asset
+func (input inputGQL) isTransfer() bool {
+	return input.Type == "UCO" || input.Type == "token"
+}
+
+func (input inputGQL) toTransfer() (ChainTransfer, error) {
+	amount, ok := new(big.Int).SetString(input.Amount.String(), 10)
+	if !ok {
+		return ChainTransfer{}, fmt.Errorf("invalid amount %q", input.Amount)
+	}
+	return ChainTransfer{
+		Address:      strings.ToUpper(input.From),
+		Amount:       amount,
+		TokenAddress: strings.ToUpper(input.TokenAddress),
+		TokenId:      input.TokenId,
+	}, nil
+}
+
+// TransactionInput is an incoming UCO or token transfer to an address
+type TransactionInput struct {
+	ChainTransfer
+	Timestamp time.Time
+	Spent     bool
+}
+
+const transactionInputsQuery = `query($address: Address!) {
+  transactionInputs(address: $address) { from amount type tokenAddress tokenId timestamp spent }
+}`
+
+// GetTransactionInputs returns the UCO and token transfers received by the address
+func GetTransactionInputs(endpoint string, address string) ([]TransactionInput, error) {
+	var result struct {
+		TransactionInputs []struct {
+			inputGQL
+			Timestamp int64 `json:"timestamp"`
+			Spent     bool  `json:"spent"`
+		} `json:"transactionInputs"`
+	}
+	err := queryGraphql(endpoint, transactionInputsQuery, map[string]interface{}{"address": address}, &result)
+	if graphqlErr, ok := err.(GraphqlError); ok && graphqlErr.notFound() {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var inputs []TransactionInput
+	for _, input := range result.TransactionInputs {
+		if !input.isTransfer() {
+			continue
+		}
+		transfer, err := input.toTransfer()
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, TransactionInput{
+			ChainTransfer: transfer,
+			Timestamp:     time.Unix(input.Timestamp, 0).UTC(),
+			Spent:         input.Spent,
+		})
+	}
+	return inputs, nil
+}
+
+// FormatTransfer describes the amount and the asset of a transfer
+func FormatTransfer(transfer ChainTransfer) string {
+	if transfer.TokenAddress == "" {
+		return archethic.FormatBigInt(transfer.Amount, 8) + " UCO"
+	}
+	return fmt.Sprintf("%s token %s#%d", archethic.FormatBigInt(transfer.Amount, 8), transfer.TokenAddress, transfer.TokenId)
+}
@@ -0,0 +1,161 @@
+package tuiutils
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileEnv is the environment variable selecting the profile, when the --profile flag is not set
+const ProfileEnv = "ARCHETHIC_PROFILE"
+
+// Config is the configuration file of the CLI, holding the named profiles
+type Config struct {
+	CurrentProfile string             `yaml:"current_profile,omitempty"`
+	Profiles       map[string]Profile `yaml:"profiles,omitempty"`
+}
+
+// Profile holds the default values of the flags of the commands
+type Profile struct {
+	// Endpoint is local, testnet, mainnet or a custom URL
+	Endpoint      string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	EllipticCurve string `yaml:"elliptic_curve,omitempty" json:"elliptic_curve,omitempty"`
+	HashAlgorithm string `yaml:"hash_algorithm,omitempty" json:"hash_algorithm,omitempty"`
+	// SeedSource is ssh, ssh:<path of the key>, mnemonic, keystore:<name of the entry>, signer:<command of the external signer> or an access seed
+	SeedSource  string `yaml:"seed_source,omitempty" json:"seed_source,omitempty"`
+	ServiceName string `yaml:"service_name,omitempty" json:"service_name,omitempty"`
+}
+
+// ConfigPath returns the path of the configuration file, in the XDG config directory
+func ConfigPath() (string, error) {
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		var err error
+		configDir, err = os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(configDir, "archethic-cli", "config.yaml"), nil
+}
+
+// LoadConfig reads the configuration file, an empty configuration is returned if it doesn't exist
+func LoadConfig() (Config, error) {
+	config := Config{Profiles: map[string]Profile{}}
+	path, err := ConfigPath()
+	if err != nil {
+		return config, err
+	}
+	configBytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+	if err := yaml.Unmarshal(configBytes, &config); err != nil {
+		return config, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	if config.Profiles == nil {
+		config.Profiles = map[string]Profile{}
+	}
+	return config, nil
+}
+
+// SaveConfig writes the configuration file, readable only by its owner as a profile can hold a seed
+func SaveConfig(config Config) error {
+	path, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	configBytes, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, configBytes, 0600)
+}
+
+// ProfileNames returns the names of the profiles, sorted
+func (c Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ActiveProfile returns the name of the selected profile: the name passed (by the --profile flag), the ARCHETHIC_PROFILE
+// environment variable or the current profile of the configuration. The name is empty if no profile is selected.
+func (c Config) ActiveProfile(name string) string {
+	if name != "" {
+		return name
+	}
+	if env := os.Getenv(ProfileEnv); env != "" {
+		return env
+	}
+	return c.CurrentProfile
+}
+
+// EndpointURL returns the URL of an endpoint: local, testnet, mainnet or a custom URL
+func EndpointURL(endpoint string) string {
+	switch endpoint {
+	case "local":
+		return "http://localhost:4000"
+	case "testnet":
+		return "https://testnet.archethic.net"
+	case "mainnet":
+		return "https://mainnet.archethic.net"
+	default:
+		_, err := url.Parse(endpoint)
+		if err != nil {
+			return ""
+		}
+		return endpoint
+	}
+}
+
+// NodeEndpoints are the node endpoints proposed by the TUI, with their URL: the networks, the profiles with an
+// endpoint and a custom URL
+type NodeEndpoints struct {
+	Names []string
+	URLs  map[string]string
+}
+
+// LoadNodeEndpoints loads the node endpoints when a model of the TUI is created. With a broken configuration file,
+// the networks and the custom URL are still returned along with the error.
+func LoadNodeEndpoints() (NodeEndpoints, error) {
+	endpoints := NodeEndpoints{
+		Names: []string{"Local", "Testnet", "Mainnet"},
+		URLs: map[string]string{
+			"Local":   EndpointURL("local"),
+			"Testnet": EndpointURL("testnet"),
+			"Mainnet": EndpointURL("mainnet"),
+			"Custom":  "",
+		},
+	}
+	config, err := LoadConfig()
+	if err != nil {
+		endpoints.Names = append(endpoints.Names, "Custom")
+		return endpoints, err
+	}
+	for _, profileName := range config.ProfileNames() {
+		profile := config.Profiles[profileName]
+		if profile.Endpoint == "" {
+			continue
+		}
+		name := "Profile " + profileName
+		endpoints.Names = append(endpoints.Names, name)
+		endpoints.URLs[name] = EndpointURL(profile.Endpoint)
+	}
+	endpoints.Names = append(endpoints.Names, "Custom")
+	return endpoints, nil
+}
@@ -0,0 +1,406 @@
+package tuiutils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// KeychainServiceSeed returns the seed from which the keys of the chain of a keychain service are derived.
+// The nodes need it to generate the transactions of the smart contracts deployed on this chain.
+func KeychainServiceSeed(keychain *archethic.Keychain, serviceName string) ([]byte, error) {
+	service, ok := keychain.Services[serviceName]
+	if !ok {
+		return nil, fmt.Errorf("service %s doesn't exist in the keychain", serviceName)
+	}
+	// with an index in the derivation path, each key has its own seed (see archethic.DeriveArchethicKeypair)
+	if strings.Count(service.DerivationPath, "/") == 3 {
+		return nil, fmt.Errorf("the keys of the service %s are not derived from a single seed, its chain can't hold a smart contract", serviceName)
+	}
+	hashedPath := sha256.Sum256([]byte(service.DerivationPath))
+	mac := hmac.New(sha512.New, keychain.Seed)
+	mac.Write(hashedPath[:])
+	return mac.Sum(nil)[:32], nil
+}
+
+// GetContractChainSeed returns the seed of the chain of the transaction: the seed of the signer,
+// or the seed of the keychain service in service mode
+func GetContractChainSeed(endpoint string, signer Signer, serviceMode bool, serviceName string) ([]byte, error) {
+	if !serviceMode {
+		seed, ok := signer.(SeedSigner)
+		if !ok {
+			return nil, ErrSignerWithoutSeed
+		}
+		return seed, nil
+	}
+	keychain, err := AccessKeychain(endpoint, signer)
+	if err != nil {
+		return nil, err
+	}
+	return KeychainServiceSeed(keychain, serviceName)
+}
+
+// AddContractOwnership adds the ownership letting the nodes generate the next transactions of the smart contract:
+// the chain seed encrypted with the secret key, and the secret key encrypted for the storage nonce public key.
+// Nothing is added if the transaction already has this ownership.
+func AddContractOwnership(transaction *archethic.TransactionBuilder, secretKey []byte, storageNouncePublicKey string, chainSeed []byte) error {
+	found, err := hasContractOwnership(transaction, secretKey, storageNouncePublicKey, chainSeed)
+	if err != nil || found {
+		return err
+	}
+
+	storageNouncePublicKeyBytes, err := hex.DecodeString(storageNouncePublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid storage nonce public key: %w", err)
+	}
+	cipher, err := archethic.AesEncrypt(chainSeed, secretKey)
+	if err != nil {
+		return err
+	}
+	encryptedSecretKey, err := archethic.EcEncrypt(secretKey, storageNouncePublicKeyBytes)
+	if err != nil {
+		return err
+	}
+	transaction.AddOwnership(cipher, []archethic.AuthorizedKey{{
+		PublicKey:          storageNouncePublicKeyBytes,
+		EncryptedSecretKey: encryptedSecretKey,
+	}})
+	return nil
+}
+
+// DescribeContractOwnership describes the ownership added by AddContractOwnership, without revealing the seed
+func DescribeContractOwnership(transaction *archethic.TransactionBuilder, secretKey []byte, storageNouncePublicKey string, chainSeed []byte) string {
+	for _, ownership := range transaction.Data.Ownerships {
+		secret, err := archethic.AesDecrypt(ownership.Secret, secretKey)
+		if err != nil || string(secret) != string(chainSeed) {
+			continue
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "Contract ownership:\n")
+		fmt.Fprintf(&b, "  secret: chain seed, encrypted (%s)\n", strings.ToUpper(hex.EncodeToString(ownership.Secret)))
+		for _, authorizedKey := range ownership.AuthorizedKeys {
+			publicKey := strings.ToUpper(hex.EncodeToString(authorizedKey.PublicKey))
+			if strings.EqualFold(publicKey, storageNouncePublicKey) {
+				fmt.Fprintf(&b, "  authorized key: %s (storage nonce public key)\n", publicKey)
+			} else {
+				fmt.Fprintf(&b, "  authorized key: %s\n", publicKey)
+			}
+		}
+		return b.String()
+	}
+	return "No contract ownership\n"
+}
+
+// hasContractOwnership returns true if an ownership of the transaction holds the chain seed as secret
+// and authorizes the storage nonce public key
+func hasContractOwnership(transaction *archethic.TransactionBuilder, secretKey []byte, storageNouncePublicKey string, chainSeed []byte) (bool, error) {
+	for _, ownership := range transaction.Data.Ownerships {
+		secret, err := archethic.AesDecrypt(ownership.Secret, secretKey)
+		if err != nil {
+			return false, err
+		}
+		if string(secret) != string(chainSeed) {
+			continue
+		}
+		for _, authorizedKey := range ownership.AuthorizedKeys {
+			if strings.EqualFold(hex.EncodeToString(authorizedKey.PublicKey), storageNouncePublicKey) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// ContractAction is a named action of a smart contract, triggered by a transaction
+type ContractAction struct {
+	Name       string
+	Parameters []string
+}
+
+// actionRegexp matches the named actions: actions triggered_by: transaction, on: name(param1, param2)
+var actionRegexp = regexp.MustCompile(`(?m)^\s*actions\s+triggered_by:\s*transaction\s*,\s*on:\s*([A-Za-z_]\w*)\s*(?:\(([^)]*)\))?`)
+
+// ParseContractActions returns the named actions declared by the code of a smart contract
+func ParseContractActions(code string) []ContractAction {
+	var actions []ContractAction
+	for _, match := range actionRegexp.FindAllStringSubmatch(code, -1) {
+		action := ContractAction{Name: match[1], Parameters: []string{}}
+		for _, parameter := range strings.Split(match[2], ",") {
+			if parameter = strings.TrimSpace(parameter); parameter != "" {
+				action.Parameters = append(action.Parameters, parameter)
+			}
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+// GetContractCode returns the code of the smart contract of the chain of the address
+func GetContractCode(endpoint string, address string) (string, error) {
+	var result struct {
+		LastTransaction struct {
+			Data struct {
+				Code string `json:"code"`
+			} `json:"data"`
+		} `json:"lastTransaction"`
+	}
+	err := queryGraphql(endpoint, `query($address: Address!) { lastTransaction(address: $address) { data { code } } }`, map[string]interface{}{"address": address}, &result)
+	if graphqlErr, ok := err.(GraphqlError); ok && graphqlErr.notFound() {
+		return "", fmt.Errorf("no transaction found for the address %s", strings.ToUpper(address))
+	}
+	if err != nil {
+		return "", err
+	}
+	return result.LastTransaction.Data.Code, nil
+}
+
+// ContractSimulationError is returned when the node rejects the execution of the contracts called by a transaction
+type ContractSimulationError struct {
+	Failures []archethic.SimulateResponse
+}
+
+func (e ContractSimulationError) Error() string {
+	var b strings.Builder
+	b.WriteString("contract simulation failed:")
+	for _, nodeError := range e.NodeErrors() {
+		b.WriteString("\n- ")
+		b.WriteString(strings.ReplaceAll(strings.TrimPrefix(nodeError.describe(), SimulationContext+": "), "\n", "\n  "))
+	}
+	return b.String()
+}
+
+// NodeErrors returns the rejection of each contract
+func (e ContractSimulationError) NodeErrors() []NodeError {
+	nodeErrors := make([]NodeError, len(e.Failures))
+	for i, failure := range e.Failures {
+		nodeErrors[i] = newNodeError(SimulationContext, failure.Error.Code, failure.Error.Message, failure.Error.Data)
+		nodeErrors[i].Recipient = strings.ToUpper(failure.RecipientAddress)
+	}
+	return nodeErrors
+}
+
+// ErrorCode returns the code of the first rejection
+func (e ContractSimulationError) ErrorCode() ErrorCode {
+	nodeErrors := e.NodeErrors()
+	if len(nodeErrors) == 0 {
+		return ErrorContractConditionFailed
+	}
+	return nodeErrors[0].ErrorCode()
+}
+
+func (e ContractSimulationError) Hint() string {
+	return errorHints[e.ErrorCode()]
+}
+
+// SimulateTransaction builds the transaction and asks the node to simulate the execution of the contracts it calls.
+// If a contract rejects it, a SendTransactionError with the TransactionRejected status is returned.
+// The built transaction can then be sent with BroadcastTransaction.
+func SimulateTransaction(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, transactionIndex uint, serviceName string, storageNouncePublicKey string, signer Signer) error {
+	err := buildTransactionToSend(transaction, secretKey, curve, serviceMode, endpoint, transactionIndex, serviceName, storageNouncePublicKey, signer)
+	if err != nil {
+		return err
+	}
+	client := archethic.NewAPIClient(endpoint)
+	responses, err := client.SimulateContractExecution(transaction)
+	if err != nil {
+		return SendTransactionError{Status: TransactionTransportError, Err: handleTransactionError(SimulationContext, err)}
+	}
+	var failures []archethic.SimulateResponse
+	for _, response := range responses {
+		if !response.Valid {
+			failures = append(failures, response)
+		}
+	}
+	if len(failures) > 0 {
+		return SendTransactionError{Status: TransactionRejected, Err: ContractSimulationError{Failures: failures}}
+	}
+	return nil
+}
+
+// ContractTrigger is a trigger of a smart contract actions, or of a condition.
+// The argument is the named action for the transaction triggers, the date or the interval for the time triggers.
+type ContractTrigger struct {
+	Type     string
+	Argument string
+}
+
+// ContractSummary describes the code of a smart contract
+type ContractSummary struct {
+	Version         string
+	Triggers        []ContractTrigger
+	Conditions      []ContractTrigger
+	Actions         []ContractAction
+	PublicFunctions []ContractAction
+}
+
+var (
+	versionRegexp        = regexp.MustCompile(`(?m)^\s*@version\s+(\S+)`)
+	triggerRegexp        = regexp.MustCompile(`(?m)^\s*actions\s+triggered_by:\s*(\w+)\s*(?:,\s*(?:on|at):\s*(.*?))?\s+do\s*$`)
+	conditionRegexp      = regexp.MustCompile(`(?m)^\s*condition\s+(?:triggered_by:\s*(\w+)\s*(?:,\s*on:\s*([^,]*?))?\s*,\s*as:|(\w+):)`)
+	publicFunctionRegexp = regexp.MustCompile(`(?m)^\s*export\s+fun\s+([A-Za-z_]\w*)\s*(?:\(([^)]*)\))?`)
+)
+
+// ParseContract summarizes the version, the triggers, the conditions, the named actions
+// and the public functions declared by the code of a smart contract
+func ParseContract(code string) ContractSummary {
+	summary := ContractSummary{
+		Triggers:        []ContractTrigger{},
+		Conditions:      []ContractTrigger{},
+		Actions:         ParseContractActions(code),
+		PublicFunctions: []ContractAction{},
+	}
+	if summary.Actions == nil {
+		summary.Actions = []ContractAction{}
+	}
+	if match := versionRegexp.FindStringSubmatch(code); match != nil {
+		summary.Version = match[1]
+	}
+	for _, match := range triggerRegexp.FindAllStringSubmatch(code, -1) {
+		summary.Triggers = append(summary.Triggers, ContractTrigger{Type: match[1], Argument: strings.TrimSpace(match[2])})
+	}
+	for _, match := range conditionRegexp.FindAllStringSubmatch(code, -1) {
+		if match[3] != "" {
+			// older syntax: condition inherit: [...], condition transaction: [...]
+			summary.Conditions = append(summary.Conditions, ContractTrigger{Type: match[3]})
+		} else {
+			summary.Conditions = append(summary.Conditions, ContractTrigger{Type: match[1], Argument: strings.TrimSpace(match[2])})
+		}
+	}
+	for _, match := range publicFunctionRegexp.FindAllStringSubmatch(code, -1) {
+		function := ContractAction{Name: match[1], Parameters: []string{}}
+		for _, parameter := range strings.Split(match[2], ",") {
+			if parameter = strings.TrimSpace(parameter); parameter != "" {
+				function.Parameters = append(function.Parameters, parameter)
+			}
+		}
+		summary.PublicFunctions = append(summary.PublicFunctions, function)
+	}
+	return summary
+}
+
+// DeployedContract is the smart contract held by the last transaction of a chain
+type DeployedContract struct {
+	Address     string
+	ChainLength uint
+	Type        string
+	Timestamp   time.Time
+	Code        string
+	// Ownerships lists the authorized public keys of each ownership of the transaction, the secrets are not fetched
+	Ownerships [][]string
+	// State is the state of the contract, nil if the node doesn't provide it
+	State map[string]interface{}
+}
+
+const deployedContractQuery = `query($address: Address!) {
+  lastTransaction(address: $address) {
+    address
+    chainLength
+    type
+    data {
+      code
+      ownerships { authorizedPublicKeys { publicKey } }
+    }
+    validationStamp { timestamp }
+  }
+}`
+
+const contractStateQuery = `query($address: Address!) {
+  lastTransaction(address: $address) {
+    validationStamp { ledgerOperations { unspentOutputs { type state } } }
+  }
+}`
+
+// GetDeployedContract returns the last transaction of the chain of the address, with its smart contract
+func GetDeployedContract(endpoint string, address string) (DeployedContract, error) {
+	var result struct {
+		LastTransaction struct {
+			Address     string `json:"address"`
+			ChainLength uint   `json:"chainLength"`
+			Type        string `json:"type"`
+			Data        struct {
+				Code       string `json:"code"`
+				Ownerships []struct {
+					AuthorizedPublicKeys []struct {
+						PublicKey string `json:"publicKey"`
+					} `json:"authorizedPublicKeys"`
+				} `json:"ownerships"`
+			} `json:"data"`
+			ValidationStamp *struct {
+				Timestamp int64 `json:"timestamp"`
+			} `json:"validationStamp"`
+		} `json:"lastTransaction"`
+	}
+	err := queryGraphql(endpoint, deployedContractQuery, map[string]interface{}{"address": address}, &result)
+	if graphqlErr, ok := err.(GraphqlError); ok && graphqlErr.notFound() {
+		return DeployedContract{}, fmt.Errorf("no transaction found for the address %s", strings.ToUpper(address))
+	}
+	if err != nil {
+		return DeployedContract{}, err
+	}
+
+	tx := result.LastTransaction
+	contract := DeployedContract{
+		Address:     strings.ToUpper(tx.Address),
+		ChainLength: tx.ChainLength,
+		Type:        tx.Type,
+		Code:        tx.Data.Code,
+		Ownerships:  [][]string{},
+	}
+	if tx.ValidationStamp != nil {
+		contract.Timestamp = time.Unix(tx.ValidationStamp.Timestamp, 0)
+	}
+	for _, ownership := range tx.Data.Ownerships {
+		publicKeys := []string{}
+		for _, authorizedKey := range ownership.AuthorizedPublicKeys {
+			publicKeys = append(publicKeys, strings.ToUpper(authorizedKey.PublicKey))
+		}
+		contract.Ownerships = append(contract.Ownerships, publicKeys)
+	}
+	if contract.Code != "" {
+		contract.State, err = getContractState(endpoint, address)
+		if err != nil {
+			return DeployedContract{}, err
+		}
+	}
+	return contract, nil
+}
+
+// getContractState returns the state held by the unspent outputs of the last transaction of the chain.
+// The nodes which don't handle the contract states reject the query, nil is returned in this case.
+func getContractState(endpoint string, address string) (map[string]interface{}, error) {
+	var result struct {
+		LastTransaction struct {
+			ValidationStamp *struct {
+				LedgerOperations struct {
+					UnspentOutputs []struct {
+						Type  string                 `json:"type"`
+						State map[string]interface{} `json:"state"`
+					} `json:"unspentOutputs"`
+				} `json:"ledgerOperations"`
+			} `json:"validationStamp"`
+		} `json:"lastTransaction"`
+	}
+	err := queryGraphql(endpoint, contractStateQuery, map[string]interface{}{"address": address}, &result)
+	if _, ok := err.(GraphqlError); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := map[string]interface{}{}
+	if result.LastTransaction.ValidationStamp != nil {
+		for _, utxo := range result.LastTransaction.ValidationStamp.LedgerOperations.UnspentOutputs {
+			if utxo.Type == "state" && utxo.State != nil {
+				state = utxo.State
+			}
+		}
+	}
+	return state, nil
+}
@@ -0,0 +1,203 @@
+package tuiutils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/ybbus/jsonrpc/v3"
+)
+
+// ErrorCode is a stable code identifying a kind of error, which can be matched by scripts
+type ErrorCode string
+
+const (
+	ErrorInsufficientFunds       ErrorCode = "insufficient_funds"
+	ErrorInvalidIndex            ErrorCode = "invalid_index"
+	ErrorInvalidSignature        ErrorCode = "invalid_signature"
+	ErrorContractConditionFailed ErrorCode = "contract_condition_failed"
+	ErrorInvalidTransaction      ErrorCode = "invalid_transaction"
+	ErrorNetworkIssue            ErrorCode = "network_issue"
+	ErrorNode                    ErrorCode = "node_error"
+)
+
+// SimulationContext is the context of the errors returned by the simulation of a contract execution
+const SimulationContext = "SIMULATION"
+
+// errorHints are the actions suggested to fix the known errors
+var errorHints = map[ErrorCode]string{
+	ErrorInsufficientFunds:       "the balance of the chain doesn't cover the transfers and the fee: check it with get-balance and fund the chain before sending the transaction again",
+	ErrorInvalidIndex:            "the index doesn't follow the last transaction of the chain: don't set the index to use the next one, or check the chain with get-chain",
+	ErrorInvalidSignature:        "the transaction is not signed with the keys of the chain: check the seed, the elliptic curve and the keychain service",
+	ErrorContractConditionFailed: "a smart contract rejected the transaction: check its conditions with get-contract, and try the call with call-contract --simulate",
+}
+
+// knownErrors classify the node errors from their exact message, or the exact reason given by the message or the
+// reason of their data, as the codes of the node are shared by different errors
+var knownErrors = map[string]ErrorCode{
+	"insufficient funds":           ErrorInsufficientFunds,
+	"invalid inherit constraints":  ErrorContractConditionFailed,
+	"invalid recipients execution": ErrorContractConditionFailed,
+	"invalid contract acceptance":  ErrorContractConditionFailed,
+	"invalid previous signature":   ErrorInvalidSignature,
+	"invalid origin signature":     ErrorInvalidSignature,
+	"invalid chain":                ErrorInvalidIndex,
+	"transaction already exists":   ErrorInvalidIndex,
+}
+
+// knownErrorDataPaths are the paths of the data holding the reason of an error
+var knownErrorDataPaths = []string{"data.message", "data.reason"}
+
+// CodedError is an error with a stable code and an optional hint
+type CodedError interface {
+	error
+	ErrorCode() ErrorCode
+	Hint() string
+}
+
+// NodeError is an error returned by a node, while validating or simulating a transaction or answering a request
+type NodeError struct {
+	// Context is the context of the error given by the node (INVALID_TRANSACTION, NETWORK_ISSUE) or SIMULATION
+	Context string `json:"context"`
+	// Recipient is the address of the contract which rejected a simulated transaction
+	Recipient string `json:"recipient,omitempty"`
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Data      any    `json:"data,omitempty"`
+	// Paths are the values of the nested data, by path
+	Paths []ErrorDataPath `json:"paths,omitempty"`
+}
+
+// ErrorDataPath is a value of the data of an error, for instance data.recipients[0].reason
+type ErrorDataPath struct {
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+func newNodeError(context string, code int, message string, data any) NodeError {
+	return NodeError{
+		Context: context,
+		Code:    code,
+		Message: message,
+		Data:    data,
+		Paths:   errorDataPaths(data, "data"),
+	}
+}
+
+func (e NodeError) Error() string {
+	message := fmt.Sprintf("%s (%d)", e.Message, e.Code)
+	if e.Recipient != "" {
+		message = fmt.Sprintf("%s: %s", e.Recipient, message)
+	}
+	if e.Context != "" {
+		message = fmt.Sprintf("%s: %s", e.Context, message)
+	}
+	return message
+}
+
+func (e NodeError) ErrorCode() ErrorCode {
+	reasons := []string{e.Message}
+	for _, path := range e.Paths {
+		for _, knownPath := range knownErrorDataPaths {
+			if path.Path == knownPath {
+				reasons = append(reasons, fmt.Sprint(path.Value))
+			}
+		}
+	}
+	for _, reason := range reasons {
+		if code, ok := knownErrors[strings.ToLower(strings.TrimSpace(reason))]; ok {
+			return code
+		}
+	}
+	switch e.Context {
+	case archethic.INVALID_TRANSACTION:
+		return ErrorInvalidTransaction
+	case archethic.NETWORK_ISSUE:
+		return ErrorNetworkIssue
+	case SimulationContext:
+		return ErrorContractConditionFailed
+	}
+	return ErrorNode
+}
+
+func (e NodeError) Hint() string {
+	return errorHints[e.ErrorCode()]
+}
+
+// describe describes the error with its data, one path per line
+func (e NodeError) describe() string {
+	var b strings.Builder
+	b.WriteString(e.Error())
+	for _, path := range e.Paths {
+		fmt.Fprintf(&b, "\n  %s: %v", path.Path, path.Value)
+	}
+	return b.String()
+}
+
+// errorDataPaths flattens the nested maps and arrays of the data of an error, the keys of the maps are sorted
+func errorDataPaths(data any, prefix string) []ErrorDataPath {
+	switch value := data.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var paths []ErrorDataPath
+		for _, key := range keys {
+			paths = append(paths, errorDataPaths(value[key], prefix+"."+key)...)
+		}
+		return paths
+	case []interface{}:
+		var paths []ErrorDataPath
+		for i, item := range value {
+			paths = append(paths, errorDataPaths(item, fmt.Sprintf("%s[%d]", prefix, i))...)
+		}
+		return paths
+	default:
+		return []ErrorDataPath{{Path: prefix, Value: value}}
+	}
+}
+
+// handleTransactionError converts the errors returned by the node to a NodeError
+func handleTransactionError(context string, err error) error {
+	if errorDetails, ok := err.(archethic.ErrorDetails); ok {
+		return newNodeError(context, errorDetails.Code, errorDetails.Message, errorDetails.Data)
+	}
+	if jsonRpcError, ok := err.(*jsonrpc.RPCError); ok {
+		return newNodeError(context, jsonRpcError.Code, jsonRpcError.Message, jsonRpcError.Data)
+	}
+	return err
+}
+
+// NodeErrors returns the errors of the node wrapped by the error
+func NodeErrors(err error) []NodeError {
+	var nodeError NodeError
+	if errors.As(err, &nodeError) {
+		return []NodeError{nodeError}
+	}
+	var simulationError ContractSimulationError
+	if errors.As(err, &simulationError) {
+		return simulationError.NodeErrors()
+	}
+	return nil
+}
+
+// DescribeError describes an error for the feedback panes: the errors of the node with their data, and the hint to fix
+// the known errors
+func DescribeError(err error) string {
+	description := err.Error()
+	var nodeError NodeError
+	if errors.As(err, &nodeError) && len(nodeError.Paths) > 0 {
+		description = strings.Replace(description, nodeError.Error(), nodeError.describe(), 1)
+	}
+	var codedError CodedError
+	if errors.As(err, &codedError) && codedError.Hint() != "" {
+		description += "\nHint: " + codedError.Hint()
+	}
+	return description
+}
@@ -0,0 +1,38 @@
+package tuiutils
+
+import (
+	"testing"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+func TestNodeErrorCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		context string
+		message string
+		data    any
+		code    ErrorCode
+		hint    bool
+	}{
+		{"known message", archethic.INVALID_TRANSACTION, "Insufficient funds", nil, ErrorInsufficientFunds, true},
+		{"known data reason", archethic.INVALID_TRANSACTION, "Invalid transaction", map[string]interface{}{"reason": "Invalid previous signature"}, ErrorInvalidSignature, true},
+		{"known data message", archethic.INVALID_TRANSACTION, "Invalid transaction", map[string]interface{}{"message": "Invalid chain"}, ErrorInvalidIndex, true},
+		{"nested reason", archethic.INVALID_TRANSACTION, "Invalid transaction", map[string]interface{}{"details": map[string]interface{}{"reason": "Insufficient funds"}}, ErrorInvalidTransaction, false},
+		{"free text", archethic.INVALID_TRANSACTION, "Invalid transaction", map[string]interface{}{"message": "the index of the condition is not a signature"}, ErrorInvalidTransaction, false},
+		{"network issue", archethic.NETWORK_ISSUE, "Consensus not reached", nil, ErrorNetworkIssue, false},
+		{"simulation", SimulationContext, "Invalid transaction", nil, ErrorContractConditionFailed, true},
+		{"other request", "", "Transaction not exists", nil, ErrorNode, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nodeError := newNodeError(test.context, -32002, test.message, test.data)
+			if code := nodeError.ErrorCode(); code != test.code {
+				t.Errorf("ErrorCode() = %s, want %s", code, test.code)
+			}
+			if hint := nodeError.Hint(); (hint != "") != test.hint {
+				t.Errorf("Hint() = %q, want a hint: %t", hint, test.hint)
+			}
+		})
+	}
+}
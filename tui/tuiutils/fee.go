@@ -0,0 +1,188 @@
+package tuiutils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// MaxFee is the maximum fee accepted for a transaction, in UCO or in a fiat currency (USD or EUR)
+type MaxFee struct {
+	Amount   string
+	Currency string
+}
+
+// TransactionChecks are the checks done before sending a transaction
+type TransactionChecks struct {
+	// MaxFee is the maximum fee accepted, nil for no limit
+	MaxFee *MaxFee
+	// CheckBalance checks that the balance of the chain covers the transfers and the fee
+	CheckBalance bool
+}
+
+// InsufficientFundsError is returned when the balance of the chain doesn't cover the transfers and the fee of a transaction
+type InsufficientFundsError struct {
+	Address    string
+	Shortfalls []string
+}
+
+func (e InsufficientFundsError) Error() string {
+	return fmt.Sprintf("insufficient funds on the chain (%s):\n- %s", e.Address, strings.Join(e.Shortfalls, "\n- "))
+}
+
+func (e InsufficientFundsError) ErrorCode() ErrorCode {
+	return ErrorInsufficientFunds
+}
+
+func (e InsufficientFundsError) Hint() string {
+	return errorHints[ErrorInsufficientFunds]
+}
+
+// ParseMaxFee parses a maximum fee: an amount, followed by the currency (UCO, USD or EUR, default to UCO)
+func ParseMaxFee(value string) (MaxFee, error) {
+	value = strings.TrimSpace(value)
+	maxFee := MaxFee{Amount: value, Currency: "UCO"}
+	for _, currency := range []string{"UCO", "USD", "EUR"} {
+		if strings.HasSuffix(strings.ToUpper(value), currency) {
+			maxFee.Amount = strings.TrimSpace(value[:len(value)-len(currency)])
+			maxFee.Currency = currency
+			break
+		}
+	}
+	amount, err := strconv.ParseFloat(maxFee.Amount, 64)
+	if err != nil || amount < 0 {
+		return MaxFee{}, fmt.Errorf("invalid maximum fee %q: expected an amount followed by UCO, USD or EUR (for instance 0.5 or 1USD)", value)
+	}
+	return maxFee, nil
+}
+
+func (m MaxFee) String() string {
+	return m.Amount + " " + m.Currency
+}
+
+// Check returns an error if the fee exceeds the maximum, a fiat maximum is compared using the rates of the fee
+func (m MaxFee) Check(fee archethic.Fee) error {
+	if m.Currency == "UCO" {
+		maxAmount, err := archethic.ParseBigInt(m.Amount, 8)
+		if err != nil {
+			return err
+		}
+		if fee.Fee.Cmp(maxAmount) > 0 {
+			return fmt.Errorf("the transaction fee (%s UCO) exceeds the maximum fee (%s)", archethic.FormatBigInt(fee.Fee, 8), m)
+		}
+		return nil
+	}
+
+	rate := float64(fee.Rates.Usd)
+	if m.Currency == "EUR" {
+		rate = float64(fee.Rates.Eur)
+	}
+	if rate <= 0 {
+		return fmt.Errorf("the %s rate of the UCO is unknown, the maximum fee can't be checked", m.Currency)
+	}
+	ucoFee, _ := strconv.ParseFloat(archethic.FormatBigInt(fee.Fee, 8), 64)
+	maxAmount, _ := strconv.ParseFloat(m.Amount, 64)
+	if ucoFee*rate > maxAmount {
+		return fmt.Errorf("the transaction fee (%s UCO, ~ %.4f %s) exceeds the maximum fee (%s)", archethic.FormatBigInt(fee.Fee, 8), ucoFee*rate, m.Currency, m)
+	}
+	return nil
+}
+
+// CheckTransaction estimates the fee of a signed transaction and runs the checks.
+// Nothing is done if no check is enabled.
+func CheckTransaction(transaction *archethic.TransactionBuilder, endpoint string, checks TransactionChecks) (archethic.Fee, error) {
+	if checks.MaxFee == nil && !checks.CheckBalance {
+		return archethic.Fee{}, nil
+	}
+	client := archethic.NewAPIClient(endpoint)
+	fee, err := client.GetTransactionFee(transaction)
+	if err != nil {
+		return archethic.Fee{}, handleTransactionError("INVALID_TRANSACTION", err)
+	}
+	return fee, CheckTransactionFee(transaction, endpoint, fee, checks)
+}
+
+// CheckTransactionFee runs the checks of a signed transaction whose fee is already estimated
+func CheckTransactionFee(transaction *archethic.TransactionBuilder, endpoint string, fee archethic.Fee, checks TransactionChecks) error {
+	if checks.MaxFee != nil {
+		if err := checks.MaxFee.Check(fee); err != nil {
+			return err
+		}
+	}
+	if checks.CheckBalance {
+		return checkBalance(transaction, endpoint, fee.Fee)
+	}
+	return nil
+}
+
+// checkBalance checks that the balance of the previous address of the transaction covers
+// the UCO transfers plus the fee, and the token transfers of each token
+func checkBalance(transaction *archethic.TransactionBuilder, endpoint string, fee *big.Int) error {
+	previousAddress, err := previousAddress(transaction)
+	if err != nil {
+		return err
+	}
+	balance, err := archethic.NewAPIClient(endpoint).GetBalance(hex.EncodeToString(previousAddress))
+	if err != nil {
+		return err
+	}
+
+	fundsError := InsufficientFundsError{Address: strings.ToUpper(hex.EncodeToString(previousAddress))}
+	transferred := new(big.Int)
+	for _, transfer := range transaction.Data.Ledger.Uco.Transfers {
+		transferred.Add(transferred, transfer.Amount)
+	}
+	needed := new(big.Int).Add(transferred, fee)
+	if needed.Cmp(balance.Uco) > 0 {
+		fundsError.Shortfalls = append(fundsError.Shortfalls, fmt.Sprintf("UCO: %s needed (%s transferred + %s fee), %s available, %s missing",
+			archethic.FormatBigInt(needed, 8), archethic.FormatBigInt(transferred, 8), archethic.FormatBigInt(fee, 8),
+			archethic.FormatBigInt(balance.Uco, 8), archethic.FormatBigInt(new(big.Int).Sub(needed, balance.Uco), 8)))
+	}
+
+	// the token transfers are summed by token, in the order of the transfers
+	var tokens []string
+	tokenAmounts := make(map[string]*big.Int)
+	for _, transfer := range transaction.Data.Ledger.Token.Transfers {
+		token := fmt.Sprintf("%s#%d", strings.ToUpper(hex.EncodeToString(transfer.TokenAddress)), transfer.TokenId)
+		if _, ok := tokenAmounts[token]; !ok {
+			tokens = append(tokens, token)
+			tokenAmounts[token] = new(big.Int)
+		}
+		tokenAmounts[token].Add(tokenAmounts[token], transfer.Amount)
+	}
+	for _, token := range tokens {
+		available := new(big.Int)
+		for _, tokenBalance := range balance.Token {
+			if fmt.Sprintf("%s#%d", strings.ToUpper(hex.EncodeToString(tokenBalance.Address)), tokenBalance.TokenId) == token {
+				available.Add(available, tokenBalance.Amount)
+			}
+		}
+		if tokenAmounts[token].Cmp(available) > 0 {
+			fundsError.Shortfalls = append(fundsError.Shortfalls, fmt.Sprintf("token %s: %s needed, %s available, %s missing",
+				token, archethic.FormatBigInt(tokenAmounts[token], 8), archethic.FormatBigInt(available, 8),
+				archethic.FormatBigInt(new(big.Int).Sub(tokenAmounts[token], available), 8)))
+		}
+	}
+
+	if len(fundsError.Shortfalls) > 0 {
+		return fundsError
+	}
+	return nil
+}
+
+// previousAddress returns the address of the previous transaction of the chain (the genesis address for the first one),
+// which holds the funds of the chain
+func previousAddress(transaction *archethic.TransactionBuilder) ([]byte, error) {
+	if len(transaction.Address) < 2 || len(transaction.PreviousPublicKey) == 0 {
+		return nil, fmt.Errorf("the transaction is not signed")
+	}
+	hashedPublicKey, err := archethic.Hash(transaction.PreviousPublicKey, archethic.HashAlgo(transaction.Address[1]))
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{transaction.Address[0]}, hashedPublicKey...), nil
+}
@@ -0,0 +1,125 @@
+package tuiutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type graphqlRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphqlError struct {
+	Message string `json:"message"`
+}
+
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphqlError  `json:"errors"`
+}
+
+// GraphqlError is returned when the node answered the query with errors
+type GraphqlError struct {
+	Messages []string
+}
+
+func (e GraphqlError) Error() string {
+	return strings.Join(e.Messages, ", ")
+}
+
+// notFound returns true if the error means that the requested transaction doesn't exist
+func (e GraphqlError) notFound() bool {
+	for _, message := range e.Messages {
+		if strings.Contains(message, "not_exists") || strings.Contains(message, "not exist") || strings.Contains(message, "not_found") {
+			return true
+		}
+	}
+	return false
+}
+
+// queryGraphql sends a query to the GraphQL API of the node and decodes the data of the response in result.
+// It is used for the queries which are not available in libgo.
+func queryGraphql(endpoint string, query string, variables map[string]interface{}, result interface{}) error {
+	requestBytes, err := json.Marshal(graphqlRequest{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	response, err := httpClient.Post(strings.TrimRight(endpoint, "/")+"/api", "application/json", bytes.NewReader(requestBytes))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("graphql query failed: %s", response.Status)
+	}
+
+	var graphqlResult graphqlResponse
+	if err := json.NewDecoder(response.Body).Decode(&graphqlResult); err != nil {
+		return err
+	}
+	if len(graphqlResult.Errors) > 0 {
+		messages := make([]string, len(graphqlResult.Errors))
+		for i, e := range graphqlResult.Errors {
+			messages[i] = e.Message
+		}
+		return GraphqlError{Messages: messages}
+	}
+	d := json.NewDecoder(bytes.NewReader(graphqlResult.Data))
+	d.UseNumber()
+	return d.Decode(result)
+}
+
+// GetLastTransactionAddress returns the address of the last transaction of the chain of the given address,
+// or the address itself if the chain doesn't have any transaction yet
+func GetLastTransactionAddress(endpoint string, address string) (string, error) {
+	lastAddress, _, err := GetChainState(endpoint, address)
+	return lastAddress, err
+}
+
+// GetChainState returns the address of the last transaction and the length of the chain of the given address.
+// If the chain doesn't have any transaction yet, the address itself and 0 are returned.
+func GetChainState(endpoint string, address string) (string, uint, error) {
+	var result struct {
+		LastTransaction struct {
+			Address     string `json:"address"`
+			ChainLength uint   `json:"chainLength"`
+		} `json:"lastTransaction"`
+	}
+	err := queryGraphql(endpoint, `query($address: Address!) { lastTransaction(address: $address) { address chainLength } }`, map[string]interface{}{"address": address}, &result)
+	if graphqlErr, ok := err.(GraphqlError); ok && graphqlErr.notFound() {
+		return strings.ToUpper(address), 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return strings.ToUpper(result.LastTransaction.Address), result.LastTransaction.ChainLength, nil
+}
+
+// TokenInfo is the description of a token needed to display the balances
+type TokenInfo struct {
+	Symbol   string
+	Decimals int
+	Type     string
+}
+
+// GetTokenInfo returns the symbol, the decimals and the type of a token
+func GetTokenInfo(endpoint string, address string) (TokenInfo, error) {
+	var result struct {
+		Token struct {
+			Symbol   string `json:"symbol"`
+			Decimals int    `json:"decimals"`
+			Type     string `json:"type"`
+		} `json:"token"`
+	}
+	err := queryGraphql(endpoint, `query($address: Address!) { token(address: $address) { symbol decimals type } }`, map[string]interface{}{"address": address}, &result)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+	return TokenInfo{Symbol: result.Token.Symbol, Decimals: result.Token.Decimals, Type: result.Token.Type}, nil
+}
@@ -0,0 +1,402 @@
+package tuiutils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// KeystoreFlag is the flag of the commands passing the name of the keystore entry of the seed
+const KeystoreFlag = "keystore"
+
+// KeystorePassphraseEnv is the environment variable giving the passphrase of the keystore, when it can't be prompted.
+// Its value can be a secret reference.
+const KeystorePassphraseEnv = "ARCHETHIC_KEYSTORE_PASSPHRASE"
+
+// KeystoreNewPassphraseEnv is the environment variable giving the new passphrase of a keystore entry, when it can't be prompted
+const KeystoreNewPassphraseEnv = "ARCHETHIC_KEYSTORE_NEW_PASSPHRASE"
+
+// The kinds of seeds of the keystore
+const (
+	KeystoreSeed         = "seed"
+	KeystoreKeychainSeed = "keychain_seed"
+)
+
+// The key derivation functions of the keystore
+const (
+	KeystoreArgon2id = "argon2id"
+	KeystoreScrypt   = "scrypt"
+)
+
+const (
+	keystoreVersion = 1
+	keystoreCipher  = "aes-256-gcm"
+	keystoreKeySize = 32
+)
+
+var keystoreNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*$`)
+
+// ErrWrongPassphrase is returned when a keystore entry can't be decrypted
+var ErrWrongPassphrase = errors.New("wrong passphrase, or the keystore entry is corrupted")
+
+// keystoreFile is the file of a keystore entry: the seed encrypted with a key derived from the passphrase
+type keystoreFile struct {
+	Version    int         `json:"version"`
+	Kind       string      `json:"kind"`
+	CreatedAt  time.Time   `json:"created_at"`
+	KDF        KeystoreKDF `json:"kdf"`
+	Cipher     string      `json:"cipher"`
+	Nonce      string      `json:"nonce"`
+	Ciphertext string      `json:"ciphertext"`
+}
+
+// KeystoreKDF is the key derivation function of a keystore entry and its parameters
+type KeystoreKDF struct {
+	Name string `json:"name"`
+	Salt string `json:"salt"`
+	// scrypt parameters
+	N int `json:"n,omitempty"`
+	R int `json:"r,omitempty"`
+	P int `json:"p,omitempty"`
+	// argon2id parameters, the memory is in KiB
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"`
+	Threads uint8  `json:"threads,omitempty"`
+}
+
+// KeystoreEntry describes an entry of the keystore, without its seed
+type KeystoreEntry struct {
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"`
+	KDF       string    `json:"kdf"`
+	CreatedAt time.Time `json:"created_at"`
+	Path      string    `json:"path"`
+}
+
+// KeystoreDir returns the directory of the keystore, next to the configuration file
+func KeystoreDir() (string, error) {
+	configPath, err := ConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "keystore"), nil
+}
+
+func keystorePath(name string) (string, error) {
+	if !keystoreNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid keystore name %q: only letters, digits, '.', '_' and '-' are allowed", name)
+	}
+	dir, err := KeystoreDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+func newKeystoreKDF(name string) (KeystoreKDF, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return KeystoreKDF{}, err
+	}
+	switch name {
+	case KeystoreArgon2id:
+		return KeystoreKDF{Name: name, Salt: hex.EncodeToString(salt), Time: 3, Memory: 64 * 1024, Threads: 4}, nil
+	case KeystoreScrypt:
+		return KeystoreKDF{Name: name, Salt: hex.EncodeToString(salt), N: 1 << 18, R: 8, P: 1}, nil
+	default:
+		return KeystoreKDF{}, fmt.Errorf("invalid key derivation function %q: must be %s or %s", name, KeystoreArgon2id, KeystoreScrypt)
+	}
+}
+
+func (kdf KeystoreKDF) deriveKey(passphrase []byte) ([]byte, error) {
+	salt, err := hex.DecodeString(kdf.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %w", err)
+	}
+	switch kdf.Name {
+	case KeystoreArgon2id:
+		return argon2.IDKey(passphrase, salt, kdf.Time, kdf.Memory, kdf.Threads, keystoreKeySize), nil
+	case KeystoreScrypt:
+		return scrypt.Key(passphrase, salt, kdf.N, kdf.R, kdf.P, keystoreKeySize)
+	default:
+		return nil, fmt.Errorf("unsupported key derivation function %q", kdf.Name)
+	}
+}
+
+// additionalData authenticates the header of the file along with the seed
+func (f keystoreFile) additionalData() ([]byte, error) {
+	return json.Marshal(struct {
+		Version int         `json:"version"`
+		Kind    string      `json:"kind"`
+		KDF     KeystoreKDF `json:"kdf"`
+		Cipher  string      `json:"cipher"`
+	}{f.Version, f.Kind, f.KDF, f.Cipher})
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptKeystoreSeed(kind string, createdAt time.Time, seed []byte, passphrase []byte, kdfName string) (keystoreFile, error) {
+	kdf, err := newKeystoreKDF(kdfName)
+	if err != nil {
+		return keystoreFile{}, err
+	}
+	file := keystoreFile{Version: keystoreVersion, Kind: kind, CreatedAt: createdAt, KDF: kdf, Cipher: keystoreCipher}
+	key, err := kdf.deriveKey(passphrase)
+	if err != nil {
+		return keystoreFile{}, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return keystoreFile{}, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return keystoreFile{}, err
+	}
+	additionalData, err := file.additionalData()
+	if err != nil {
+		return keystoreFile{}, err
+	}
+	file.Nonce = hex.EncodeToString(nonce)
+	file.Ciphertext = hex.EncodeToString(gcm.Seal(nil, nonce, seed, additionalData))
+	return file, nil
+}
+
+func (f keystoreFile) decrypt(passphrase []byte) ([]byte, error) {
+	if f.Version != keystoreVersion || f.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("unsupported keystore entry (version %d, cipher %s)", f.Version, f.Cipher)
+	}
+	key, err := f.KDF.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(f.Nonce)
+	if err != nil || len(nonce) != gcm.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	ciphertext, err := hex.DecodeString(f.Ciphertext)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	additionalData, err := f.additionalData()
+	if err != nil {
+		return nil, err
+	}
+	seed, err := gcm.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return seed, nil
+}
+
+func readKeystoreFile(name string) (keystoreFile, string, error) {
+	path, err := keystorePath(name)
+	if err != nil {
+		return keystoreFile{}, "", err
+	}
+	fileBytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return keystoreFile{}, path, fmt.Errorf("the keystore entry %s doesn't exist", name)
+	}
+	if err != nil {
+		return keystoreFile{}, path, err
+	}
+	var file keystoreFile
+	if err := json.Unmarshal(fileBytes, &file); err != nil {
+		return keystoreFile{}, path, fmt.Errorf("invalid keystore entry %s: %w", name, err)
+	}
+	return file, path, nil
+}
+
+func writeKeystoreFile(path string, file keystoreFile, overwrite bool) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	fileBytes, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if !overwrite {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("the keystore entry %s already exists", strings.TrimSuffix(filepath.Base(path), ".json"))
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(fileBytes); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	// write a temporary file first, so that the entry is never lost
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, fileBytes, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// SaveKeystoreSeed encrypts the seed with the passphrase and adds it to the keystore, an existing entry is not replaced
+func SaveKeystoreSeed(name string, kind string, seed []byte, passphrase []byte, kdf string) (KeystoreEntry, error) {
+	path, err := keystorePath(name)
+	if err != nil {
+		return KeystoreEntry{}, err
+	}
+	file, err := encryptKeystoreSeed(kind, time.Now().UTC().Truncate(time.Second), seed, passphrase, kdf)
+	if err != nil {
+		return KeystoreEntry{}, err
+	}
+	if err := writeKeystoreFile(path, file, false); err != nil {
+		return KeystoreEntry{}, err
+	}
+	return KeystoreEntry{Name: name, Kind: kind, KDF: kdf, CreatedAt: file.CreatedAt, Path: path}, nil
+}
+
+// LoadKeystoreSeed decrypts the seed of a keystore entry
+func LoadKeystoreSeed(name string, passphrase []byte) ([]byte, error) {
+	file, _, err := readKeystoreFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return file.decrypt(passphrase)
+}
+
+// ChangeKeystorePassphrase encrypts the seed of a keystore entry with a new passphrase, and a new salt
+func ChangeKeystorePassphrase(name string, passphrase []byte, newPassphrase []byte, kdf string) (KeystoreEntry, error) {
+	file, path, err := readKeystoreFile(name)
+	if err != nil {
+		return KeystoreEntry{}, err
+	}
+	seed, err := file.decrypt(passphrase)
+	if err != nil {
+		return KeystoreEntry{}, err
+	}
+	if kdf == "" {
+		kdf = file.KDF.Name
+	}
+	newFile, err := encryptKeystoreSeed(file.Kind, file.CreatedAt, seed, newPassphrase, kdf)
+	if err != nil {
+		return KeystoreEntry{}, err
+	}
+	if err := writeKeystoreFile(path, newFile, true); err != nil {
+		return KeystoreEntry{}, err
+	}
+	return KeystoreEntry{Name: name, Kind: newFile.Kind, KDF: kdf, CreatedAt: newFile.CreatedAt, Path: path}, nil
+}
+
+// RemoveKeystoreEntry deletes the file of a keystore entry
+func RemoveKeystoreEntry(name string) (KeystoreEntry, error) {
+	entry, err := GetKeystoreEntry(name)
+	if err != nil {
+		return KeystoreEntry{}, err
+	}
+	return entry, os.Remove(entry.Path)
+}
+
+// GetKeystoreEntry describes an entry of the keystore
+func GetKeystoreEntry(name string) (KeystoreEntry, error) {
+	file, path, err := readKeystoreFile(name)
+	if err != nil {
+		return KeystoreEntry{}, err
+	}
+	return KeystoreEntry{Name: name, Kind: file.Kind, KDF: file.KDF.Name, CreatedAt: file.CreatedAt, Path: path}, nil
+}
+
+// ListKeystore returns the entries of the keystore, sorted by name
+func ListKeystore() ([]KeystoreEntry, error) {
+	dir, err := KeystoreDir()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	entries := []KeystoreEntry{}
+	for _, path := range paths {
+		entry, err := GetKeystoreEntry(strings.TrimSuffix(filepath.Base(path), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// KeystorePassphrase returns the passphrase of the keystore entry: the ARCHETHIC_KEYSTORE_PASSPHRASE environment variable,
+// or a prompt if the standard input is a terminal
+func KeystorePassphrase(name string) ([]byte, error) {
+	return readPassphrase(KeystorePassphraseEnv, "Enter the passphrase of the keystore entry "+name+": ", false)
+}
+
+// NewKeystorePassphrase returns the passphrase of a new keystore entry, from the environment variable, or a prompt
+// asking it twice if the standard input is a terminal
+func NewKeystorePassphrase(env string, name string) ([]byte, error) {
+	return readPassphrase(env, "Enter the new passphrase of the keystore entry "+name+": ", true)
+}
+
+func readPassphrase(env string, message string, confirm bool) ([]byte, error) {
+	if value, ok := os.LookupEnv(env); ok {
+		passphrase, err := ResolveSecret(value, "keystore passphrase")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", env, err)
+		}
+		if passphrase == "" {
+			return nil, fmt.Errorf("%s: the passphrase is empty", env)
+		}
+		return []byte(passphrase), nil
+	}
+	if !terminal.IsTerminal(int(syscall.Stdin)) {
+		return nil, fmt.Errorf("can't prompt for the passphrase, the standard input is not a terminal: set %s", env)
+	}
+	passphrase := promptSecret(message)
+	if passphrase == "" {
+		return nil, errors.New("the passphrase is empty")
+	}
+	if confirm && promptSecret("Confirm the passphrase: ") != passphrase {
+		return nil, errors.New("the passphrases don't match")
+	}
+	return []byte(passphrase), nil
+}
+
+// GetKeystoreSeed decrypts the seed of a keystore entry, with the passphrase of KeystorePassphrase
+func GetKeystoreSeed(name string) ([]byte, error) {
+	if _, err := GetKeystoreEntry(name); err != nil {
+		return nil, err
+	}
+	passphrase, err := KeystorePassphrase(name)
+	if err != nil {
+		return nil, err
+	}
+	return LoadKeystoreSeed(name, passphrase)
+}
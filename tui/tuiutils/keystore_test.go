@@ -0,0 +1,147 @@
+package tuiutils
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	seed := []byte("0123456789abcdef0123456789abcdef")
+	for _, kdf := range []string{KeystoreArgon2id, KeystoreScrypt} {
+		t.Run(kdf, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+			entry, err := SaveKeystoreSeed("main", KeystoreSeed, seed, []byte("passphrase"), kdf)
+			if err != nil {
+				t.Fatalf("SaveKeystoreSeed: %s", err)
+			}
+			if entry.KDF != kdf || entry.Kind != KeystoreSeed {
+				t.Errorf("entry = %+v, want the %s kdf and the %s kind", entry, kdf, KeystoreSeed)
+			}
+			info, err := os.Stat(entry.Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != 0600 {
+				t.Errorf("file mode = %o, want 600", info.Mode().Perm())
+			}
+			fileBytes, err := os.ReadFile(entry.Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Contains(fileBytes, seed) {
+				t.Error("the seed is written in clear")
+			}
+
+			loaded, err := LoadKeystoreSeed("main", []byte("passphrase"))
+			if err != nil {
+				t.Fatalf("LoadKeystoreSeed: %s", err)
+			}
+			if !bytes.Equal(loaded, seed) {
+				t.Errorf("seed = %x, want %x", loaded, seed)
+			}
+			if _, err := LoadKeystoreSeed("main", []byte("wrong passphrase")); !errors.Is(err, ErrWrongPassphrase) {
+				t.Errorf("LoadKeystoreSeed with a wrong passphrase: %v, want %v", err, ErrWrongPassphrase)
+			}
+			if _, err := SaveKeystoreSeed("main", KeystoreSeed, seed, []byte("passphrase"), kdf); err == nil {
+				t.Error("an existing entry is replaced")
+			}
+		})
+	}
+}
+
+func TestChangeKeystorePassphrase(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	seed := []byte("keychain seed")
+	if _, err := SaveKeystoreSeed("keychain", KeystoreKeychainSeed, seed, []byte("old"), KeystoreArgon2id); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ChangeKeystorePassphrase("keychain", []byte("wrong"), []byte("new"), ""); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("ChangeKeystorePassphrase with a wrong passphrase: %v, want %v", err, ErrWrongPassphrase)
+	}
+	entry, err := ChangeKeystorePassphrase("keychain", []byte("old"), []byte("new"), "")
+	if err != nil {
+		t.Fatalf("ChangeKeystorePassphrase: %s", err)
+	}
+	if entry.KDF != KeystoreArgon2id || entry.Kind != KeystoreKeychainSeed {
+		t.Errorf("entry = %+v, want the kdf and the kind of the entry", entry)
+	}
+	if _, err := LoadKeystoreSeed("keychain", []byte("old")); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("LoadKeystoreSeed with the old passphrase: %v, want %v", err, ErrWrongPassphrase)
+	}
+	loaded, err := LoadKeystoreSeed("keychain", []byte("new"))
+	if err != nil || !bytes.Equal(loaded, seed) {
+		t.Errorf("LoadKeystoreSeed with the new passphrase = %q, %v, want %q", loaded, err, seed)
+	}
+}
+
+func TestKeystoreTampering(t *testing.T) {
+	passphrase := []byte("passphrase")
+	file, err := encryptKeystoreSeed(KeystoreSeed, time.Now().UTC(), []byte("seed"), passphrase, KeystoreArgon2id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// flipHex changes the first byte of an hexadecimal value
+	flipHex := func(value string) string {
+		if value[0] == '0' {
+			return "1" + value[1:]
+		}
+		return "0" + value[1:]
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(f *keystoreFile)
+		err    string
+	}{
+		{"ciphertext", func(f *keystoreFile) { f.Ciphertext = flipHex(f.Ciphertext) }, ErrWrongPassphrase.Error()},
+		{"truncated ciphertext", func(f *keystoreFile) { f.Ciphertext = f.Ciphertext[:len(f.Ciphertext)-2] }, ErrWrongPassphrase.Error()},
+		{"nonce", func(f *keystoreFile) { f.Nonce = flipHex(f.Nonce) }, ErrWrongPassphrase.Error()},
+		{"invalid nonce", func(f *keystoreFile) { f.Nonce = "zz" }, ErrWrongPassphrase.Error()},
+		{"kind", func(f *keystoreFile) { f.Kind = KeystoreKeychainSeed }, ErrWrongPassphrase.Error()},
+		{"salt", func(f *keystoreFile) { f.KDF.Salt = flipHex(f.KDF.Salt) }, ErrWrongPassphrase.Error()},
+		{"kdf parameters", func(f *keystoreFile) { f.KDF.Time++ }, ErrWrongPassphrase.Error()},
+		{"kdf", func(f *keystoreFile) { f.KDF.Name = "pbkdf2" }, "unsupported key derivation function"},
+		{"version", func(f *keystoreFile) { f.Version++ }, "unsupported keystore entry"},
+		{"cipher", func(f *keystoreFile) { f.Cipher = "chacha20-poly1305" }, "unsupported keystore entry"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tampered := file
+			test.tamper(&tampered)
+			seed, err := tampered.decrypt(passphrase)
+			if err == nil {
+				t.Fatalf("tampered entry decrypted to %q", seed)
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("error = %q, want %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestKeystoreName(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"main", true},
+		{"team.deploy-key_2", true},
+		{".hidden", false},
+		{"../main", false},
+		{"dir/main", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		_, err := keystorePath(test.name)
+		if (err == nil) != test.valid {
+			t.Errorf("keystorePath(%q): %v, want valid: %t", test.name, err, test.valid)
+		}
+	}
+}
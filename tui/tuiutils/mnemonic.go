@@ -0,0 +1,383 @@
+package tuiutils
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/pflag"
+	"github.com/tyler-smith/go-bip39"
+	"github.com/tyler-smith/go-bip39/wordlists"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultMnemonicLanguage is the language of the mnemonics when none is given
+const DefaultMnemonicLanguage = "english"
+
+// The flags of the commands passing the options of the mnemonics
+const (
+	MnemonicLanguageFlag   = "mnemonic-language"
+	MnemonicPassphraseFlag = "mnemonic-passphrase"
+)
+
+// maxSuggestions is the number of closest words reported for an invalid mnemonic
+const maxSuggestions = 5
+
+// MnemonicWordCounts are the numbers of words of the BIP39 mnemonics, from 128 to 256 bits of entropy
+var MnemonicWordCounts = []int{12, 15, 18, 21, 24}
+
+// mnemonicWordLists are the BIP39 wordlists, by language
+var mnemonicWordLists = map[string][]string{
+	"english":             wordlists.English,
+	"french":              wordlists.French,
+	"spanish":             wordlists.Spanish,
+	"italian":             wordlists.Italian,
+	"czech":               wordlists.Czech,
+	"japanese":            wordlists.Japanese,
+	"korean":              wordlists.Korean,
+	"chinese_simplified":  wordlists.ChineseSimplified,
+	"chinese_traditional": wordlists.ChineseTraditional,
+}
+
+// MnemonicLanguages returns the languages of the BIP39 wordlists, sorted by name
+func MnemonicLanguages() []string {
+	languages := make([]string, 0, len(mnemonicWordLists))
+	for language := range mnemonicWordLists {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// normalizedWordLists are the indexes of the words of the wordlists, normalized as the words of a mnemonic
+var (
+	normalizedWordLists     map[string]map[string]int
+	normalizedWordListsOnce sync.Once
+)
+
+// MnemonicOptions are the options of the conversion of a mnemonic to a seed
+type MnemonicOptions struct {
+	// Language of the wordlist of the mnemonic, every wordlist is tried when it is empty
+	Language string
+	// Passphrase of the BIP39 seed, the seed being the entropy of the mnemonic when it is empty
+	Passphrase string
+}
+
+// GetMnemonicOptions returns the options of the mnemonics passed by the flags, if the command has them
+func GetMnemonicOptions(flags *pflag.FlagSet) (MnemonicOptions, error) {
+	options := MnemonicOptions{}
+	if flags.Lookup(MnemonicLanguageFlag) != nil {
+		options.Language, _ = flags.GetString(MnemonicLanguageFlag)
+		if options.Language != "" {
+			if _, err := mnemonicWordList(options.Language); err != nil {
+				return options, err
+			}
+		}
+	}
+	if flags.Lookup(MnemonicPassphraseFlag) != nil && flags.Changed(MnemonicPassphraseFlag) {
+		passphrase, _ := flags.GetString(MnemonicPassphraseFlag)
+		passphrase, err := ResolveSecret(passphrase, "mnemonic passphrase")
+		if err != nil {
+			return options, err
+		}
+		options.Passphrase = passphrase
+	}
+	return options, nil
+}
+
+func mnemonicWordList(language string) ([]string, error) {
+	wordList, ok := mnemonicWordLists[strings.ToLower(language)]
+	if !ok {
+		return nil, fmt.Errorf("unknown mnemonic language %q: must be one of %s", language, strings.Join(MnemonicLanguages(), ", "))
+	}
+	return wordList, nil
+}
+
+// withWordList runs the function with the wordlist of the bip39 package, which is global, and restores the previous one
+func withWordList(wordList []string, f func() error) error {
+	previous := bip39.GetWordList()
+	bip39.SetWordList(wordList)
+	defer bip39.SetWordList(previous)
+	return f()
+}
+
+// NewSeed returns a random seed, whose mnemonic has the number of words (see MnemonicWordCounts)
+func NewSeed(words int) ([]byte, error) {
+	if validMnemonicWordCount(words) {
+		// each word holds 11 bits, of which 1 bit of checksum for each 32 bits of entropy
+		return bip39.NewEntropy(words * 32 / 3)
+	}
+	return nil, fmt.Errorf("invalid number of words %d: must be 12, 15, 18, 21 or 24", words)
+}
+
+// NewMnemonic returns the BIP39 mnemonic of the seed in the language, the seed being the entropy of the mnemonic
+func NewMnemonic(seed []byte, language string) (string, error) {
+	wordList, err := mnemonicWordList(language)
+	if err != nil {
+		return "", err
+	}
+	var mnemonic string
+	err = withWordList(wordList, func() error {
+		mnemonic, err = bip39.NewMnemonic(seed)
+		return err
+	})
+	return mnemonic, err
+}
+
+// MnemonicQuiz returns the positions (starting at 1) of the words asked back to verify the backup of a mnemonic,
+// in random order
+func MnemonicQuiz(mnemonic string, count int) ([]int, error) {
+	words := len(strings.Fields(mnemonic))
+	if count > words {
+		count = words
+	}
+	positions := make([]int, 0, count)
+	for len(positions) < count {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(words)))
+		if err != nil {
+			return nil, err
+		}
+		position := int(n.Int64()) + 1
+		found := false
+		for _, p := range positions {
+			found = found || p == position
+		}
+		if !found {
+			positions = append(positions, position)
+		}
+	}
+	return positions, nil
+}
+
+// MnemonicWordMatches checks the word at the position (starting at 1) of the mnemonic,
+// regardless of the case and the unicode normalization
+func MnemonicWordMatches(mnemonic string, position int, word string) bool {
+	words := strings.Fields(mnemonic)
+	if position < 1 || position > len(words) {
+		return false
+	}
+	normalize := func(s string) string {
+		return norm.NFKD.String(strings.ToLower(strings.TrimSpace(s)))
+	}
+	return normalize(words[position-1]) == normalize(word)
+}
+
+// normalizeMnemonic returns the words of a mnemonic in lower case and NFKD form, as the BIP39 seeds
+func normalizeMnemonic(mnemonic string) []string {
+	return strings.Fields(norm.NFKD.String(strings.ToLower(mnemonic)))
+}
+
+func getNormalizedWordLists() map[string]map[string]int {
+	normalizedWordListsOnce.Do(func() {
+		normalizedWordLists = make(map[string]map[string]int, len(mnemonicWordLists))
+		for language, wordList := range mnemonicWordLists {
+			indexes := make(map[string]int, len(wordList))
+			for i, word := range wordList {
+				indexes[norm.NFKD.String(word)] = i
+			}
+			normalizedWordLists[language] = indexes
+		}
+	})
+	return normalizedWordLists
+}
+
+// mnemonicLanguages returns the languages to try for a mnemonic, English first when none is given
+func mnemonicLanguages(language string) []string {
+	if language != "" {
+		return []string{strings.ToLower(language)}
+	}
+	languages := []string{DefaultMnemonicLanguage}
+	for _, l := range MnemonicLanguages() {
+		if l != DefaultMnemonicLanguage {
+			languages = append(languages, l)
+		}
+	}
+	return languages
+}
+
+// LooksLikeMnemonic reports whether the value is meant as a mnemonic rather than a seed: it has 12 to 24 words,
+// give or take a missing or extra word, and at least three quarters of them are from the same wordlist
+func LooksLikeMnemonic(value string) bool {
+	words := normalizeMnemonic(value)
+	if len(words) < MnemonicWordCounts[0]-1 || len(words) > MnemonicWordCounts[len(MnemonicWordCounts)-1]+1 {
+		return false
+	}
+	for _, indexes := range getNormalizedWordLists() {
+		known := 0
+		for _, word := range words {
+			if _, ok := indexes[word]; ok {
+				known++
+			}
+		}
+		if known*4 >= len(words)*3 {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractSeedFromMnemonic returns the seed of a BIP39 mnemonic of 12 to 24 words, in the language of the options or
+// in any language. The seed is the entropy of the mnemonic, or the BIP39 seed if the options have a passphrase.
+// The error of an invalid mnemonic reports the closest words of the unknown or wrong words.
+func ExtractSeedFromMnemonic(mnemonic string, options MnemonicOptions) ([]byte, error) {
+	words := normalizeMnemonic(mnemonic)
+	if !validMnemonicWordCount(len(words)) {
+		return nil, fmt.Errorf("a mnemonic has 12, 15, 18, 21 or 24 words, not %d", len(words))
+	}
+	if options.Language != "" {
+		if _, err := mnemonicWordList(options.Language); err != nil {
+			return nil, err
+		}
+	}
+
+	var checksumErr, unknownErr error
+	unknownWords := len(words) + 1
+	for _, language := range mnemonicLanguages(options.Language) {
+		wordList := mnemonicWordLists[language]
+		indexes := getNormalizedWordLists()[language]
+		// the words as written in the wordlist
+		listWords := make([]string, len(words))
+		unknown := []int{}
+		for i, word := range words {
+			index, ok := indexes[word]
+			if !ok {
+				unknown = append(unknown, i)
+				continue
+			}
+			listWords[i] = wordList[index]
+		}
+		if len(unknown) > 0 {
+			// the error of the language with the fewest unknown words is reported
+			if len(unknown) < unknownWords {
+				unknownWords = len(unknown)
+				unknownErr = unknownWordsError(language, words, unknown)
+			}
+			continue
+		}
+
+		var entropy []byte
+		err := withWordList(wordList, func() error {
+			var err error
+			entropy, err = bip39.EntropyFromMnemonic(strings.Join(listWords, " "))
+			return err
+		})
+		if errors.Is(err, bip39.ErrChecksumIncorrect) {
+			if checksumErr == nil {
+				checksumErr = checksumError(language, listWords)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if options.Passphrase == "" {
+			return entropy, nil
+		}
+		return bip39.NewSeed(norm.NFKD.String(strings.Join(listWords, " ")), norm.NFKD.String(options.Passphrase)), nil
+	}
+	if checksumErr != nil {
+		return nil, checksumErr
+	}
+	return nil, unknownErr
+}
+
+func validMnemonicWordCount(words int) bool {
+	for _, count := range MnemonicWordCounts {
+		if count == words {
+			return true
+		}
+	}
+	return false
+}
+
+// unknownWordsError reports the words missing from the wordlist, with their closest words
+func unknownWordsError(language string, words []string, unknown []int) error {
+	wordList := mnemonicWordLists[language]
+	descriptions := make([]string, len(unknown))
+	for i, position := range unknown {
+		descriptions[i] = fmt.Sprintf("#%d %q", position+1, words[position])
+		if closest := closestWords(words[position], wordList); len(closest) > 0 {
+			descriptions[i] += " (closest: " + strings.Join(closest, ", ") + ")"
+		}
+	}
+	return fmt.Errorf("invalid mnemonic, unknown words of the %s wordlist: %s", language, strings.Join(descriptions, "; "))
+}
+
+// checksumError reports the close words that would give a valid checksum, as a word is likely mistaken for another one
+func checksumError(language string, words []string) error {
+	wordList := mnemonicWordLists[language]
+	suggestions := []string{}
+	withWordList(wordList, func() error {
+		candidate := make([]string, len(words))
+		for position, word := range words {
+			normalized := norm.NFKD.String(word)
+			for _, other := range wordList {
+				if len(suggestions) == maxSuggestions {
+					return nil
+				}
+				if other == word || wordDistance(norm.NFKD.String(other), normalized) > 2 {
+					continue
+				}
+				copy(candidate, words)
+				candidate[position] = other
+				if bip39.IsMnemonicValid(strings.Join(candidate, " ")) {
+					suggestions = append(suggestions, fmt.Sprintf("#%d %q instead of %q", position+1, other, word))
+				}
+			}
+		}
+		return nil
+	})
+	if len(suggestions) == 0 {
+		return fmt.Errorf("invalid checksum of the %s mnemonic: a word is wrong or the words are not in order", language)
+	}
+	return fmt.Errorf("invalid checksum of the %s mnemonic, the closest valid words are: %s", language, strings.Join(suggestions, "; "))
+}
+
+// closestWords returns the words of the wordlist at the smallest edit distance of the word, if it is small enough
+func closestWords(word string, wordList []string) []string {
+	best := 3
+	closest := []string{}
+	for _, candidate := range wordList {
+		distance := wordDistance(word, norm.NFKD.String(candidate))
+		if distance < best {
+			best = distance
+			closest = closest[:0]
+		}
+		if distance == best && len(closest) < maxSuggestions {
+			closest = append(closest, candidate)
+		}
+	}
+	return closest
+}
+
+// wordDistance returns the Levenshtein distance of two words
+func wordDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
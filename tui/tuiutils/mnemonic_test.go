@@ -0,0 +1,157 @@
+package tuiutils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tyler-smith/go-bip39"
+	"github.com/tyler-smith/go-bip39/wordlists"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestMnemonicRoundTrip(t *testing.T) {
+	for _, language := range MnemonicLanguages() {
+		for _, words := range MnemonicWordCounts {
+			seed := bytes.Repeat([]byte{byte(words)}, words*32/3/8)
+			mnemonic, err := NewMnemonic(seed, language)
+			if err != nil {
+				t.Fatalf("%s, %d words: NewMnemonic: %s", language, words, err)
+			}
+			if count := len(strings.Fields(mnemonic)); count != words {
+				t.Errorf("%s: %d words, want %d", language, count, words)
+			}
+
+			extracted, err := ExtractSeedFromMnemonic(mnemonic, MnemonicOptions{Language: language})
+			if err != nil || !bytes.Equal(extracted, seed) {
+				t.Errorf("%s, %d words: ExtractSeedFromMnemonic = %x, %v, want %x", language, words, extracted, err, seed)
+			}
+			// the words can be written in upper case and in any unicode normalization
+			written := strings.ToUpper(norm.NFC.String(mnemonic))
+			extracted, err = ExtractSeedFromMnemonic(written, MnemonicOptions{Language: language})
+			if err != nil || !bytes.Equal(extracted, seed) {
+				t.Errorf("%s, %d words, in upper case: ExtractSeedFromMnemonic = %x, %v, want %x", language, words, extracted, err, seed)
+			}
+		}
+	}
+}
+
+func TestExtractSeedFromMnemonicLanguage(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x5a}, 16)
+	for _, language := range []string{"english", "french", "spanish", "japanese", "korean"} {
+		mnemonic, err := NewMnemonic(seed, language)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the language is found when it isn't given
+		extracted, err := ExtractSeedFromMnemonic(mnemonic, MnemonicOptions{})
+		if err != nil || !bytes.Equal(extracted, seed) {
+			t.Errorf("%s: ExtractSeedFromMnemonic = %x, %v, want %x", language, extracted, err, seed)
+		}
+		if language == "english" {
+			continue
+		}
+		if _, err := ExtractSeedFromMnemonic(mnemonic, MnemonicOptions{Language: "english"}); err == nil {
+			t.Errorf("%s: the mnemonic is accepted as english", language)
+		}
+	}
+	if _, err := ExtractSeedFromMnemonic("abandon", MnemonicOptions{Language: "klingon"}); err == nil {
+		t.Error("an unknown language is accepted")
+	}
+}
+
+func TestExtractSeedFromMnemonicPassphrase(t *testing.T) {
+	mnemonic, err := NewMnemonic(bytes.Repeat([]byte{0x7f}, 32), "english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	extracted, err := ExtractSeedFromMnemonic(mnemonic, MnemonicOptions{Passphrase: "TREZOR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := bip39.NewSeed(mnemonic, "TREZOR"); !bytes.Equal(extracted, want) {
+		t.Errorf("seed = %x, want the BIP39 seed %x", extracted, want)
+	}
+}
+
+func TestExtractSeedFromMnemonicErrors(t *testing.T) {
+	mnemonic, err := NewMnemonic(make([]byte, 16), "english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	words := strings.Fields(mnemonic)
+	replace := func(position int, word string) string {
+		replaced := append([]string{}, words...)
+		replaced[position] = word
+		return strings.Join(replaced, " ")
+	}
+
+	tests := []struct {
+		name     string
+		mnemonic string
+		err      string
+	}{
+		{"word count", strings.Join(words[:11], " "), "a mnemonic has 12, 15, 18, 21 or 24 words, not 11"},
+		{"unknown word", replace(3, "abandom"), `#4 "abandom" (closest: abandon`},
+		{"unknown word without close word", replace(0, "zzzzzzzz"), `unknown words of the english wordlist: #1 "zzzzzzzz"`},
+		// the mnemonic of a zero seed ends with "about", "above" has the same length but not the checksum
+		{"checksum", replace(11, "above"), `invalid checksum of the english mnemonic, the closest valid words are: #12 "about" instead of "above"`},
+		{"word order", strings.Join(append([]string{words[11]}, words[:11]...), " "), "invalid checksum of the english mnemonic"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ExtractSeedFromMnemonic(test.mnemonic, MnemonicOptions{})
+			if err == nil {
+				t.Fatal("invalid mnemonic accepted")
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("error = %q, want %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestMnemonicRestoresWordList(t *testing.T) {
+	previous := bip39.GetWordList()
+	t.Cleanup(func() { bip39.SetWordList(previous) })
+	bip39.SetWordList(wordlists.Italian)
+
+	japanese, err := NewMnemonic(make([]byte, 16), "japanese")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExtractSeedFromMnemonic(japanese, MnemonicOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	english, err := NewMnemonic(make([]byte, 16), "english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an invalid checksum tries the close words with the wordlist of the mnemonic
+	ExtractSeedFromMnemonic(strings.Replace(english, "about", "above", 1), MnemonicOptions{})
+
+	if wordList := bip39.GetWordList(); wordList[0] != wordlists.Italian[0] {
+		t.Errorf("the bip39 wordlist starts with %q, want the italian wordlist", wordList[0])
+	}
+}
+
+func TestLooksLikeMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic(make([]byte, 16), "french")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{mnemonic, true},
+		{strings.Join(strings.Fields(mnemonic)[:11], " "), true},
+		{"00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff", false},
+		{"one two three four five six seven eight nine ten eleven twelve", false},
+	}
+	for _, test := range tests {
+		if got := LooksLikeMnemonic(test.value); got != test.want {
+			t.Errorf("LooksLikeMnemonic(%q) = %t, want %t", test.value, got, test.want)
+		}
+	}
+}
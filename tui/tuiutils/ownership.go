@@ -0,0 +1,38 @@
+package tuiutils
+
+import (
+	"bytes"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// DecryptedOwnership is the secret of an ownership of a transaction, decrypted with an authorized key
+type DecryptedOwnership struct {
+	// Index is the position of the ownership in the transaction
+	Index  int
+	Secret []byte
+}
+
+// DecryptOwnerships decrypts the secrets of the ownerships authorizing the public key:
+// the secret key encrypted for the public key is decrypted by decryptSecretKey, then the secret with the secret key
+func DecryptOwnerships(ownerships []archethic.Ownership, publicKey []byte, decryptSecretKey func(cipherText []byte) ([]byte, error)) ([]DecryptedOwnership, error) {
+	var decrypted []DecryptedOwnership
+	for i, ownership := range ownerships {
+		for _, authorizedKey := range ownership.AuthorizedKeys {
+			if !bytes.Equal(authorizedKey.PublicKey, publicKey) {
+				continue
+			}
+			secretKey, err := decryptSecretKey(authorizedKey.EncryptedSecretKey)
+			if err != nil {
+				return nil, err
+			}
+			secret, err := archethic.AesDecrypt(ownership.Secret, secretKey)
+			if err != nil {
+				return nil, err
+			}
+			decrypted = append(decrypted, DecryptedOwnership{Index: i, Secret: secret})
+			break
+		}
+	}
+	return decrypted, nil
+}
@@ -0,0 +1,131 @@
+package tuiutils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	archethic "github.com/archethic-foundation/libgo"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// the standard input can only be read once: its secret is kept for the next references to the same secret,
+// named by its label, and the references to another secret are rejected
+var (
+	stdinSecret      string
+	stdinSecretLabel string
+)
+
+// IsSecretReference returns true if the value is a reference to a secret rather than the secret itself
+func IsSecretReference(value string) bool {
+	if value == "-" {
+		return true
+	}
+	for _, prefix := range []string{"env:", "file:", "fd:", "cmd:"} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveSecret returns the secret of a reference:
+//   - env:NAME reads the environment variable NAME
+//   - file:PATH reads the file PATH
+//   - fd:N reads the file descriptor N, opened by the parent process
+//   - cmd:COMMAND runs the command with the shell and reads its standard output
+//   - - reads the standard input, or prompts for the secret if it is a terminal, the label naming the secret
+//
+// The leading and trailing spaces and newlines are removed. Any other value is the secret itself.
+// The errors never contain the secret.
+func ResolveSecret(value string, label string) (string, error) {
+	switch {
+	case value == "-":
+		return readStdinSecret(label)
+	case strings.HasPrefix(value, "env:"):
+		name := strings.TrimPrefix(value, "env:")
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("the environment variable %s is not set", name)
+		}
+		return strings.TrimSpace(secret), nil
+	case strings.HasPrefix(value, "file:"):
+		path := strings.TrimPrefix(value, "file:")
+		secret, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("can't read the secret file: %w", err)
+		}
+		return strings.TrimSpace(string(secret)), nil
+	case strings.HasPrefix(value, "fd:"):
+		fd, err := strconv.ParseUint(strings.TrimPrefix(value, "fd:"), 10, 32)
+		if err != nil {
+			return "", fmt.Errorf("invalid file descriptor %q", strings.TrimPrefix(value, "fd:"))
+		}
+		file := os.NewFile(uintptr(fd), value)
+		defer file.Close()
+		secret, err := io.ReadAll(file)
+		if err != nil {
+			return "", fmt.Errorf("can't read the secret from the file descriptor %d: %w", fd, err)
+		}
+		return strings.TrimSpace(string(secret)), nil
+	case strings.HasPrefix(value, "cmd:"):
+		command := strings.TrimPrefix(value, "cmd:")
+		var stdout bytes.Buffer
+		cmd := exec.Command("sh", "-c", command)
+		// the command can prompt for a passphrase on the terminal
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = &stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("the secret command %q failed: %w", command, err)
+		}
+		return strings.TrimSpace(stdout.String()), nil
+	default:
+		return value, nil
+	}
+}
+
+func readStdinSecret(label string) (string, error) {
+	if stdinSecretLabel != "" {
+		if stdinSecretLabel != label {
+			return "", fmt.Errorf("the standard input already provided the %s, it can't also provide the %s", stdinSecretLabel, label)
+		}
+		return stdinSecret, nil
+	}
+	var secret string
+	if terminal.IsTerminal(int(syscall.Stdin)) {
+		secret = promptSecret("Enter the " + label + ": ")
+	} else {
+		secretBytes, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("can't read the secret from the standard input: %w", err)
+		}
+		secret = string(secretBytes)
+	}
+	stdinSecret = strings.TrimSpace(secret)
+	stdinSecretLabel = label
+	return stdinSecret, nil
+}
+
+// ParseSeed returns the seed of a value: a secret reference (see ResolveSecret), resolved to a seed,
+// or the seed itself, as a BIP39 mnemonic (see ExtractSeedFromMnemonic), hexadecimal or raw bytes
+func ParseSeed(value string, mnemonicOptions MnemonicOptions) ([]byte, error) {
+	seed, err := ResolveSecret(value, "seed")
+	if err != nil {
+		return nil, err
+	}
+	if IsSecretReference(value) && seed == "" {
+		return nil, errors.New("the secret of " + value + " is empty")
+	}
+	// a value looking like a mnemonic is not parsed as a seed, an invalid mnemonic is reported
+	if LooksLikeMnemonic(seed) {
+		return ExtractSeedFromMnemonic(seed, mnemonicOptions)
+	}
+	return archethic.MaybeConvertToHex(seed)
+}
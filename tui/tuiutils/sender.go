@@ -0,0 +1,179 @@
+package tuiutils
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+const (
+	// AllConfirmations waits until every node of the replication tree has confirmed the transaction
+	AllConfirmations uint = math.MaxUint
+	// DefaultTimeout is the default number of seconds to wait for the transaction confirmations
+	DefaultTimeout uint = 60
+)
+
+// SendTransactionStatus describes how the sending of a transaction ended
+type SendTransactionStatus int
+
+const (
+	TransactionSent SendTransactionStatus = iota
+	TransactionConfirmed
+	TransactionUnconfirmed
+	TransactionRejected
+	TransactionTimeout
+	TransactionTransportError
+)
+
+func (s SendTransactionStatus) String() string {
+	switch s {
+	case TransactionSent:
+		return "sent"
+	case TransactionConfirmed:
+		return "confirmed"
+	case TransactionUnconfirmed:
+		return "unconfirmed"
+	case TransactionRejected:
+		return "rejected"
+	case TransactionTimeout:
+		return "timeout"
+	case TransactionTransportError:
+		return "transport error"
+	default:
+		return "unknown"
+	}
+}
+
+// SendTransactionError is returned when a transaction didn't reach the expected number of confirmations
+type SendTransactionError struct {
+	Status          SendTransactionStatus
+	NbConfirmations uint
+	Err             error
+}
+
+func (e SendTransactionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e SendTransactionError) Unwrap() error {
+	return e.Err
+}
+
+type sendResult struct {
+	status          SendTransactionStatus
+	nbConfirmations uint
+	err             error
+}
+
+// senderContext is the context of the errors of the sending of the transaction, as for the sender of libgo
+const senderContext = "SENDER"
+
+// sendTransactionAndWait sends a signed transaction and blocks until it is sent (if waitConfirmations is 0),
+// until waitConfirmations confirmations are received (or all of them if the replication tree is smaller),
+// until the transaction is rejected or until the timeout (in seconds) expires.
+// The subscriptions are closed before returning: unlike the sender of libgo, no goroutine or websocket is left behind.
+func sendTransactionAndWait(endpoint string, transaction *archethic.TransactionBuilder, waitConfirmations uint, timeout uint) (uint, error) {
+	// the handlers are called from the goroutines of the websockets, only the first result is kept
+	results := make(chan sendResult, 1)
+	publish := func(result sendResult) {
+		select {
+		case results <- result:
+		default:
+		}
+	}
+	transportError := func(err error) (uint, error) {
+		return 0, SendTransactionError{
+			Status: TransactionTransportError,
+			Err:    handleTransactionError(senderContext, archethic.ErrorDetails{Message: err.Error(), Code: -1}),
+		}
+	}
+
+	address := hex.EncodeToString(transaction.Address)
+	var nbConfirmations atomic.Uint64
+	confirmedQuery := `subscription($address: Address!) { transactionConfirmed(address: $address) { nbConfirmations maxConfirmations } }`
+	confirmed, err := Subscribe(endpoint, confirmedQuery, map[string]interface{}{"address": address}, func(data map[string]interface{}) {
+		var response struct {
+			TransactionConfirmed archethic.TransactionConfirmedGQL
+		}
+		if decodeSubscriptionData(data, &response) != nil {
+			return
+		}
+		nbConf, maxConf := response.TransactionConfirmed.NbConfirmations, response.TransactionConfirmed.MaxConfirmations
+		nbConfirmations.Store(uint64(nbConf))
+		if waitConfirmations > 0 && (nbConf >= waitConfirmations || nbConf >= maxConf) {
+			publish(sendResult{status: TransactionConfirmed, nbConfirmations: nbConf})
+		}
+	})
+	if err != nil {
+		return transportError(err)
+	}
+	defer confirmed.Close()
+
+	errorQuery := `subscription($address: Address!) { transactionError(address: $address) { context error { code data message } } }`
+	rejected, err := Subscribe(endpoint, errorQuery, map[string]interface{}{"address": address}, func(data map[string]interface{}) {
+		var response struct {
+			TransactionError archethic.TransactionErrorGQL
+		}
+		if decodeSubscriptionData(data, &response) != nil {
+			return
+		}
+		context := string(response.TransactionError.Context)
+		status := TransactionTransportError
+		if context == archethic.INVALID_TRANSACTION {
+			status = TransactionRejected
+		}
+		publish(sendResult{status: status, nbConfirmations: uint(nbConfirmations.Load()), err: handleTransactionError(context, response.TransactionError.Error)})
+	})
+	if err != nil {
+		return transportError(err)
+	}
+	defer rejected.Close()
+
+	if _, err := archethic.NewAPIClient(endpoint).SendTransaction(transaction); err != nil {
+		return transportError(err)
+	}
+	if waitConfirmations == 0 {
+		publish(sendResult{status: TransactionSent})
+	}
+
+	var result sendResult
+	select {
+	case result = <-results:
+	case <-time.After(time.Duration(timeout) * time.Second):
+		result = timeoutResult(uint(nbConfirmations.Load()), waitConfirmations)
+	}
+
+	if result.err != nil {
+		return result.nbConfirmations, SendTransactionError{Status: result.status, NbConfirmations: result.nbConfirmations, Err: result.err}
+	}
+	return result.nbConfirmations, nil
+}
+
+// decodeSubscriptionData decodes the data of a subscription message into the response
+func decodeSubscriptionData(data map[string]interface{}, response interface{}) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonData, response)
+}
+
+func timeoutResult(nbConfirmations uint, waitConfirmations uint) sendResult {
+	if nbConfirmations == 0 {
+		return sendResult{status: TransactionTimeout, err: fmt.Errorf("timeout: no confirmation received")}
+	}
+	expected := fmt.Sprint(waitConfirmations)
+	if waitConfirmations == AllConfirmations {
+		expected = "all"
+	}
+	return sendResult{
+		status:          TransactionUnconfirmed,
+		nbConfirmations: nbConfirmations,
+		err:             fmt.Errorf("transaction sent but unconfirmed: received %d of %s expected confirmations", nbConfirmations, expected),
+	}
+}
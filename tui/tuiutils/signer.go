@@ -0,0 +1,395 @@
+package tuiutils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/pflag"
+)
+
+// SignerFlag is the flag of the commands passing the command of the external signer, in place of the seed
+const SignerFlag = "signer"
+
+// signerProtocolVersion is the version of the requests sent to the external signers
+const signerProtocolVersion = 1
+
+// The operations requested to the external signers
+const (
+	SignerPublicKey = "public_key"
+	SignerSign      = "sign"
+	SignerDecrypt   = "decrypt"
+)
+
+// ErrSignerWithoutSeed is returned when an operation needs the seed of the chain, which an external signer doesn't share
+var ErrSignerWithoutSeed = errors.New("the seed of the chain is needed to let the nodes generate the transactions of a smart contract, which an external signer doesn't share: use the seed, or a keychain service")
+
+// Signer holds the keys of a chain, used to build its transactions in place of its seed
+type Signer interface {
+	// PublicKey returns the public key of the chain at the index
+	PublicKey(curve archethic.Curve, index uint32) ([]byte, error)
+	// Sign signs the payload with the private key of the chain at the index, it returns the public key and the signature
+	Sign(curve archethic.Curve, index uint32, payload []byte) ([]byte, []byte, error)
+	// Decrypt decrypts a secret encrypted for the public key of the chain at the index (see archethic.EcEncrypt)
+	Decrypt(curve archethic.Curve, index uint32, cipherText []byte) ([]byte, error)
+}
+
+// SeedSigner derives the keys of the chain from its seed
+type SeedSigner []byte
+
+func (s SeedSigner) PublicKey(curve archethic.Curve, index uint32) ([]byte, error) {
+	publicKey, _, err := archethic.DeriveKeypair(s, index, curve)
+	return publicKey, err
+}
+
+func (s SeedSigner) Sign(curve archethic.Curve, index uint32, payload []byte) ([]byte, []byte, error) {
+	publicKey, privateKey, err := archethic.DeriveKeypair(s, index, curve)
+	if err != nil {
+		return nil, nil, err
+	}
+	signature, err := archethic.Sign(privateKey, payload)
+	return publicKey, signature, err
+}
+
+func (s SeedSigner) Decrypt(curve archethic.Curve, index uint32, cipherText []byte) ([]byte, error) {
+	_, privateKey, err := archethic.DeriveKeypair(s, index, curve)
+	if err != nil {
+		return nil, err
+	}
+	return archethic.EcDecrypt(cipherText, privateKey)
+}
+
+// ExternalSigner delegates the keys of the chain to a helper command, such as a bridge to an HSM or a vault service.
+// The command is run by the shell for each operation: it reads a SignerRequest in JSON on its standard input
+// and writes a SignerResponse in JSON on its standard output.
+type ExternalSigner struct {
+	Command string
+}
+
+// SignerRequest is the request sent to an external signer
+type SignerRequest struct {
+	Version   int    `json:"version"`
+	Operation string `json:"operation"`
+	Curve     string `json:"curve"`
+	Index     uint32 `json:"index"`
+	// Payload is the hexadecimal payload to sign, the transaction without its previous public key and signatures
+	Payload string `json:"payload,omitempty"`
+	// CipherText is the hexadecimal secret to decrypt
+	CipherText string `json:"ciphertext,omitempty"`
+}
+
+// SignerResponse is the response of an external signer, the values are in hexadecimal
+type SignerResponse struct {
+	PublicKey string `json:"public_key,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Plaintext string `json:"plaintext,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (s ExternalSigner) PublicKey(curve archethic.Curve, index uint32) ([]byte, error) {
+	response, err := s.call(SignerRequest{Operation: SignerPublicKey, Curve: GetCurveName(curve), Index: index})
+	if err != nil {
+		return nil, err
+	}
+	return decodeSignerPublicKey(response, curve)
+}
+
+func (s ExternalSigner) Sign(curve archethic.Curve, index uint32, payload []byte) ([]byte, []byte, error) {
+	response, err := s.call(SignerRequest{Operation: SignerSign, Curve: GetCurveName(curve), Index: index, Payload: hex.EncodeToString(payload)})
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKey, err := decodeSignerPublicKey(response, curve)
+	if err != nil {
+		return nil, nil, err
+	}
+	signature, err := hex.DecodeString(response.Signature)
+	if err != nil || len(signature) == 0 {
+		return nil, nil, errors.New("the external signer returned an invalid signature")
+	}
+	// the signature is checked here, the nodes would only reject the transaction
+	valid, err := archethic.Verify(signature, payload, publicKey)
+	if err != nil || !valid {
+		return nil, nil, errors.New("the signature returned by the external signer doesn't match its public key")
+	}
+	return publicKey, signature, nil
+}
+
+func (s ExternalSigner) Decrypt(curve archethic.Curve, index uint32, cipherText []byte) ([]byte, error) {
+	response, err := s.call(SignerRequest{Operation: SignerDecrypt, Curve: GetCurveName(curve), Index: index, CipherText: hex.EncodeToString(cipherText)})
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := hex.DecodeString(response.Plaintext)
+	if err != nil || len(plaintext) == 0 {
+		return nil, errors.New("the external signer returned an invalid plaintext")
+	}
+	return plaintext, nil
+}
+
+// call runs the command of the signer with the request, the errors of the signer are returned as is
+func (s ExternalSigner) call(request SignerRequest) (SignerResponse, error) {
+	request.Version = signerProtocolVersion
+	input, err := json.Marshal(request)
+	if err != nil {
+		return SignerResponse{}, err
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command("sh", "-c", s.Command)
+	cmd.Stdin = bytes.NewReader(append(input, '\n'))
+	cmd.Stdout = &stdout
+	// the signer can report its progress, or prompt on the terminal
+	cmd.Stderr = os.Stderr
+	runErr := cmd.Run()
+
+	var response SignerResponse
+	decodeErr := json.Unmarshal(stdout.Bytes(), &response)
+	switch {
+	case decodeErr == nil && response.Error != "":
+		return SignerResponse{}, fmt.Errorf("external signer: %s", response.Error)
+	case runErr != nil:
+		return SignerResponse{}, fmt.Errorf("external signer: %w", runErr)
+	case decodeErr != nil:
+		return SignerResponse{}, fmt.Errorf("external signer: invalid response to the %s request: %w", request.Operation, decodeErr)
+	}
+	return response, nil
+}
+
+func decodeSignerPublicKey(response SignerResponse, curve archethic.Curve) ([]byte, error) {
+	publicKey, err := hex.DecodeString(response.PublicKey)
+	// the public keys start with their curve and origin
+	if err != nil || len(publicKey) < 3 || publicKey[0] != byte(curve) {
+		return nil, fmt.Errorf("the external signer returned an invalid %s public key", GetCurveName(curve))
+	}
+	return publicKey, nil
+}
+
+// GetSigner returns the external signer of the --signer flag if it is set, otherwise the signer of the seed (see GetSeedBytes)
+func GetSigner(flags *pflag.FlagSet, sshFlagKey, sshPathFlagKey, seedFlagKey, mnemonicFlag string) (Signer, error) {
+	if flags.Lookup(SignerFlag) != nil && flags.Changed(SignerFlag) {
+		command, _ := flags.GetString(SignerFlag)
+		if strings.TrimSpace(command) == "" {
+			return nil, errors.New("the command of the external signer is empty")
+		}
+		return ExternalSigner{Command: command}, nil
+	}
+	seed, err := GetSeedBytes(flags, sshFlagKey, sshPathFlagKey, seedFlagKey, mnemonicFlag)
+	if err != nil {
+		return nil, err
+	}
+	return SeedSigner(seed), nil
+}
+
+// SignerAddress returns the address of the chain at the index
+func SignerAddress(signer Signer, index uint32, curve archethic.Curve, hashAlgo archethic.HashAlgo) ([]byte, error) {
+	if seed, ok := signer.(SeedSigner); ok {
+		return archethic.DeriveAddress(seed, index, curve, hashAlgo)
+	}
+	publicKey, err := signer.PublicKey(curve, index)
+	if err != nil {
+		return nil, err
+	}
+	hashedPublicKey, err := archethic.Hash(publicKey, hashAlgo)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(curve)}, hashedPublicKey...), nil
+}
+
+// BuildTransaction sets the address and the previous signature of the transaction at the index of the chain,
+// as TransactionBuilder.Build does with a seed
+func BuildTransaction(transaction *archethic.TransactionBuilder, signer Signer, index uint32, curve archethic.Curve, hashAlgo archethic.HashAlgo) error {
+	if seed, ok := signer.(SeedSigner); ok {
+		return transaction.Build(seed, index, curve, hashAlgo)
+	}
+	address, err := SignerAddress(signer, index+1, curve, hashAlgo)
+	if err != nil {
+		return err
+	}
+	transaction.SetAddress(address)
+	publicKey, signature, err := signer.Sign(curve, index, previousSignaturePayload(transaction))
+	if err != nil {
+		return err
+	}
+	transaction.SetPreviousSignatureAndPreviousPublicKey(signature, publicKey)
+	return nil
+}
+
+// previousSignaturePayload returns the payload signed by the previous key of the chain:
+// the origin signature payload of the transaction without its previous public key and signature
+func previousSignaturePayload(transaction *archethic.TransactionBuilder) []byte {
+	unsigned := *transaction
+	unsigned.SetPreviousSignatureAndPreviousPublicKey(nil, nil)
+	payload := unsigned.OriginSignaturePayload()
+	// remove the size of the empty previous signature
+	return payload[:len(payload)-1]
+}
+
+// GetKeychain fetches and decrypts the keychain of the access chain of the signer, as archethic.GetKeychain does with a seed
+func GetKeychain(signer Signer, client archethic.APIClient) (*archethic.Keychain, error) {
+	if seed, ok := signer.(SeedSigner); ok {
+		return archethic.GetKeychain(seed, client)
+	}
+	publicKey, err := signer.PublicKey(archethic.ED25519, 0)
+	if err != nil {
+		return nil, err
+	}
+	accessKeychainAddress, err := SignerAddress(signer, 1, archethic.ED25519, archethic.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	accessOwnerships, err := client.GetTransactionOwnerships(hex.EncodeToString(accessKeychainAddress))
+	if err != nil {
+		if strings.Contains(err.Error(), "transaction_not_exists") {
+			return nil, errors.New("access keychain doesn't exist")
+		}
+		return nil, err
+	}
+	if len(accessOwnerships) == 0 {
+		return nil, errors.New("keychain doesn't exist")
+	}
+	keychainAddress, err := decryptSignerOwnership(signer, publicKey, accessOwnerships[0])
+	if err != nil {
+		return nil, err
+	}
+
+	keychainOwnerships, err := client.GetLastTransactionOwnerships(hex.EncodeToString(keychainAddress))
+	if err != nil {
+		return nil, err
+	}
+	if len(keychainOwnerships) == 0 {
+		return nil, errors.New("keychain doesn't exist")
+	}
+	encodedKeychain, err := decryptSignerOwnership(signer, publicKey, keychainOwnerships[0])
+	if err != nil {
+		return nil, err
+	}
+	keychain := archethic.DecodeKeychain(encodedKeychain)
+	for _, authorizedKey := range keychainOwnerships[0].AuthorizedKeys {
+		keychain.AddAuthorizedPublicKey(authorizedKey.PublicKey)
+	}
+	return keychain, nil
+}
+
+// decryptSignerOwnership decrypts the secret of the ownership with the secret key encrypted for the access public key
+func decryptSignerOwnership(signer Signer, publicKey []byte, ownership archethic.Ownership) ([]byte, error) {
+	for _, authorizedKey := range ownership.AuthorizedKeys {
+		if bytes.Equal(authorizedKey.PublicKey, publicKey) {
+			secretKey, err := signer.Decrypt(archethic.ED25519, 0, authorizedKey.EncryptedSecretKey)
+			if err != nil {
+				return nil, err
+			}
+			return archethic.AesDecrypt(ownership.Secret, secretKey)
+		}
+	}
+	return nil, errors.New("the access public key is not authorized to decrypt the keychain")
+}
+
+// NewAccessTransaction creates the transaction of the access chain of the signer to the keychain,
+// as archethic.NewAccessTransaction does with a seed
+func NewAccessTransaction(signer Signer, keychainAddress []byte) (*archethic.TransactionBuilder, error) {
+	if seed, ok := signer.(SeedSigner); ok {
+		return archethic.NewAccessTransaction(seed, keychainAddress)
+	}
+	publicKey, err := signer.PublicKey(archethic.ED25519, 0)
+	if err != nil {
+		return nil, err
+	}
+	secretKey := make([]byte, 32)
+	if _, err := rand.Read(secretKey); err != nil {
+		return nil, err
+	}
+	encryptedSecretKey, err := archethic.EcEncrypt(secretKey, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	encryptedKeychainAddress, err := archethic.AesEncrypt(keychainAddress, secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := archethic.NewTransaction(archethic.KeychainAccessType)
+	transaction.AddOwnership(encryptedKeychainAddress, []archethic.AuthorizedKey{
+		{PublicKey: publicKey, EncryptedSecretKey: encryptedSecretKey},
+	})
+	err = BuildTransaction(transaction, signer, 0, archethic.ED25519, archethic.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// ServeSignerRequest answers the request read from the reader with the keys of the signer, as an external signer does.
+// The error of the request is also written in the response.
+func ServeSignerRequest(signer Signer, reader io.Reader, writer io.Writer) error {
+	response, err := serveSignerRequest(signer, reader)
+	if err != nil {
+		response = SignerResponse{Error: err.Error()}
+	}
+	if encodeErr := json.NewEncoder(writer).Encode(response); encodeErr != nil {
+		return encodeErr
+	}
+	return err
+}
+
+func serveSignerRequest(signer Signer, reader io.Reader) (SignerResponse, error) {
+	var request SignerRequest
+	if err := json.NewDecoder(reader).Decode(&request); err != nil {
+		return SignerResponse{}, fmt.Errorf("invalid request: %w", err)
+	}
+	if request.Version != signerProtocolVersion {
+		return SignerResponse{}, fmt.Errorf("unsupported request version %d", request.Version)
+	}
+	curve, err := parseCurveName(request.Curve)
+	if err != nil {
+		return SignerResponse{}, err
+	}
+
+	switch request.Operation {
+	case SignerPublicKey:
+		publicKey, err := signer.PublicKey(curve, request.Index)
+		if err != nil {
+			return SignerResponse{}, err
+		}
+		return SignerResponse{PublicKey: hex.EncodeToString(publicKey)}, nil
+	case SignerSign:
+		payload, err := hex.DecodeString(request.Payload)
+		if err != nil || len(payload) == 0 {
+			return SignerResponse{}, errors.New("invalid payload")
+		}
+		publicKey, signature, err := signer.Sign(curve, request.Index, payload)
+		if err != nil {
+			return SignerResponse{}, err
+		}
+		return SignerResponse{PublicKey: hex.EncodeToString(publicKey), Signature: hex.EncodeToString(signature)}, nil
+	case SignerDecrypt:
+		cipherText, err := hex.DecodeString(request.CipherText)
+		if err != nil || len(cipherText) == 0 {
+			return SignerResponse{}, errors.New("invalid ciphertext")
+		}
+		plaintext, err := signer.Decrypt(curve, request.Index, cipherText)
+		if err != nil {
+			return SignerResponse{}, err
+		}
+		return SignerResponse{Plaintext: hex.EncodeToString(plaintext)}, nil
+	}
+	return SignerResponse{}, fmt.Errorf("unknown operation %q", request.Operation)
+}
+
+func parseCurveName(name string) (archethic.Curve, error) {
+	for _, curve := range []archethic.Curve{archethic.ED25519, archethic.P256, archethic.SECP256K1} {
+		if GetCurveName(curve) == name {
+			return curve, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown curve %q", name)
+}
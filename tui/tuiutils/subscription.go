@@ -0,0 +1,159 @@
+package tuiutils
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nshafer/phx"
+)
+
+// subscriptionTimeout is the time to wait for the websocket connection and the subscription reply
+const subscriptionTimeout = 15 * time.Second
+
+// Subscription is a GraphQL subscription over the Absinthe websocket of the node.
+// Unlike the subscriptions of libgo, it keeps receiving the messages until it is closed.
+type Subscription struct {
+	socket *phx.Socket
+}
+
+// websocketURL returns the url of the Absinthe websocket of the endpoint
+func websocketURL(endpoint string) (*url.URL, error) {
+	endpointURL, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	switch endpointURL.Scheme {
+	case "http":
+		endpointURL.Scheme = "ws"
+	case "https":
+		endpointURL.Scheme = "wss"
+	default:
+		return nil, fmt.Errorf("invalid endpoint %q", endpoint)
+	}
+	endpointURL.Path += "/socket"
+	return endpointURL, nil
+}
+
+// Subscribe sends the GraphQL subscription to the node and calls the handler with the data of each message
+func Subscribe(endpoint string, query string, variables map[string]interface{}, handler func(data map[string]interface{})) (*Subscription, error) {
+	endpointURL, err := websocketURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	socket := phx.NewSocket(endpointURL)
+	subscription := &Subscription{socket: socket}
+
+	opened := make(chan struct{}, 1)
+	socket.OnOpen(func() {
+		select {
+		case opened <- struct{}{}:
+		default:
+		}
+	})
+	// the socket keeps retrying to connect until it is disconnected
+	if err := socket.Connect(); err != nil {
+		return nil, err
+	}
+	select {
+	case <-opened:
+	case <-time.After(subscriptionTimeout):
+		subscription.Close()
+		return nil, errors.New("websocket connection timeout")
+	}
+
+	channel := socket.Channel("__absinthe__:control", nil)
+	join, err := channel.Join()
+	if err != nil {
+		subscription.Close()
+		return nil, err
+	}
+	if _, err := waitReply(join); err != nil {
+		subscription.Close()
+		return nil, fmt.Errorf("websocket join: %w", err)
+	}
+
+	// the messages are only forwarded once the id of the subscription is known
+	var mu sync.Mutex
+	subscriptionId := ""
+	socket.OnMessage(func(message phx.Message) {
+		mu.Lock()
+		id := subscriptionId
+		mu.Unlock()
+		if id == "" || message.Topic != id || message.Event != "subscription:data" {
+			return
+		}
+		payload, _ := message.Payload.(map[string]interface{})
+		result, _ := payload["result"].(map[string]interface{})
+		data, ok := result["data"].(map[string]interface{})
+		if ok {
+			handler(data)
+		}
+	})
+
+	payload := map[string]interface{}{"query": query}
+	if variables != nil {
+		payload["variables"] = variables
+	}
+	doc, err := channel.Push("doc", payload)
+	if err != nil {
+		subscription.Close()
+		return nil, err
+	}
+	response, err := waitReply(doc)
+	if err != nil {
+		subscription.Close()
+		return nil, fmt.Errorf("subscription: %w", err)
+	}
+	responseMap, _ := response.(map[string]interface{})
+	id, ok := responseMap["subscriptionId"].(string)
+	if !ok || id == "" {
+		subscription.Close()
+		return nil, fmt.Errorf("subscription: invalid reply %v", response)
+	}
+	mu.Lock()
+	subscriptionId = id
+	mu.Unlock()
+
+	return subscription, nil
+}
+
+// waitReply waits for the reply of the node to a push
+func waitReply(push *phx.Push) (interface{}, error) {
+	type reply struct {
+		response interface{}
+		err      error
+	}
+	replies := make(chan reply, 3)
+	push.Receive("ok", func(response any) {
+		replies <- reply{response: response}
+	})
+	push.Receive("error", func(response any) {
+		replies <- reply{err: fmt.Errorf("%v", response)}
+	})
+	push.Receive("timeout", func(response any) {
+		replies <- reply{err: errors.New("timeout")}
+	})
+	select {
+	case r := <-replies:
+		return r.response, r.err
+	case <-time.After(subscriptionTimeout):
+		return nil, errors.New("timeout")
+	}
+}
+
+// Close stops the subscription and disconnects the websocket
+func (s *Subscription) Close() {
+	s.socket.Disconnect()
+}
+
+// SubscribeTransactionConfirmed calls the handler each time the transaction of the address gets confirmed by new nodes
+func SubscribeTransactionConfirmed(endpoint string, address string, handler func()) (*Subscription, error) {
+	query := `subscription($address: Address!) { transactionConfirmed(address: $address) { nbConfirmations maxConfirmations } }`
+	return Subscribe(endpoint, query, map[string]interface{}{"address": address}, func(map[string]interface{}) {
+		handler()
+	})
+}
@@ -0,0 +1,269 @@
+package tuiutils
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// TransactionFile is the portable JSON representation of a transaction.
+// Binary fields are hex encoded so a signed transaction can be moved between hosts without loss.
+type TransactionFile struct {
+	Version           uint32              `json:"version"`
+	Address           string              `json:"address"`
+	Type              string              `json:"type"`
+	Data              TransactionFileData `json:"data"`
+	PreviousPublicKey string              `json:"previousPublicKey"`
+	PreviousSignature string              `json:"previousSignature"`
+	OriginSignature   string              `json:"originSignature"`
+}
+
+type TransactionFileData struct {
+	Content    string                     `json:"content"`
+	Code       string                     `json:"code"`
+	Ledger     TransactionFileLedger      `json:"ledger"`
+	Ownerships []TransactionFileOwnership `json:"ownerships"`
+	Recipients []TransactionFileRecipient `json:"recipients"`
+}
+
+type TransactionFileLedger struct {
+	Uco   []TransactionFileUcoTransfer   `json:"uco"`
+	Token []TransactionFileTokenTransfer `json:"token"`
+}
+
+type TransactionFileUcoTransfer struct {
+	To     string   `json:"to"`
+	Amount *big.Int `json:"amount"`
+}
+
+type TransactionFileTokenTransfer struct {
+	To           string   `json:"to"`
+	Amount       *big.Int `json:"amount"`
+	TokenAddress string   `json:"tokenAddress"`
+	TokenID      uint     `json:"tokenId"`
+}
+
+type TransactionFileOwnership struct {
+	Secret         string                         `json:"secret"`
+	AuthorizedKeys []TransactionFileAuthorizedKey `json:"authorizedKeys"`
+}
+
+type TransactionFileAuthorizedKey struct {
+	PublicKey          string `json:"publicKey"`
+	EncryptedSecretKey string `json:"encryptedSecretKey"`
+}
+
+type TransactionFileRecipient struct {
+	Address string        `json:"address"`
+	Action  string        `json:"action,omitempty"`
+	Args    []interface{} `json:"args,omitempty"`
+}
+
+var transactionTypeNames = map[string]archethic.TransactionType{
+	"keychain_access": archethic.KeychainAccessType,
+	"keychain":        archethic.KeychainType,
+	"transfer":        archethic.TransferType,
+	"hosting":         archethic.HostingType,
+	"token":           archethic.TokenType,
+	"data":            archethic.DataType,
+	"contract":        archethic.ContractType,
+	"code_proposal":   archethic.CodeProposalType,
+	"code_approval":   archethic.CodeApprovalType,
+}
+
+func NewTransactionFile(transaction *archethic.TransactionBuilder) (TransactionFile, error) {
+	txType, err := transaction.TxType.String()
+	if err != nil {
+		return TransactionFile{}, err
+	}
+
+	data := TransactionFileData{
+		Content: hex.EncodeToString(transaction.Data.Content),
+		Code:    string(transaction.Data.Code),
+		Ledger: TransactionFileLedger{
+			Uco:   make([]TransactionFileUcoTransfer, len(transaction.Data.Ledger.Uco.Transfers)),
+			Token: make([]TransactionFileTokenTransfer, len(transaction.Data.Ledger.Token.Transfers)),
+		},
+		Ownerships: make([]TransactionFileOwnership, len(transaction.Data.Ownerships)),
+		Recipients: make([]TransactionFileRecipient, len(transaction.Data.Recipients)),
+	}
+
+	for i, t := range transaction.Data.Ledger.Uco.Transfers {
+		data.Ledger.Uco[i] = TransactionFileUcoTransfer{
+			To:     hex.EncodeToString(t.To),
+			Amount: t.Amount,
+		}
+	}
+	for i, t := range transaction.Data.Ledger.Token.Transfers {
+		data.Ledger.Token[i] = TransactionFileTokenTransfer{
+			To:           hex.EncodeToString(t.To),
+			Amount:       t.Amount,
+			TokenAddress: hex.EncodeToString(t.TokenAddress),
+			TokenID:      t.TokenId,
+		}
+	}
+	for i, o := range transaction.Data.Ownerships {
+		authorizedKeys := make([]TransactionFileAuthorizedKey, len(o.AuthorizedKeys))
+		for j, k := range o.AuthorizedKeys {
+			authorizedKeys[j] = TransactionFileAuthorizedKey{
+				PublicKey:          hex.EncodeToString(k.PublicKey),
+				EncryptedSecretKey: hex.EncodeToString(k.EncryptedSecretKey),
+			}
+		}
+		data.Ownerships[i] = TransactionFileOwnership{
+			Secret:         hex.EncodeToString(o.Secret),
+			AuthorizedKeys: authorizedKeys,
+		}
+	}
+	for i, r := range transaction.Data.Recipients {
+		data.Recipients[i] = TransactionFileRecipient{
+			Address: hex.EncodeToString(r.Address),
+			Action:  string(r.Action),
+			Args:    r.Args,
+		}
+	}
+
+	return TransactionFile{
+		Version:           transaction.Version,
+		Address:           hex.EncodeToString(transaction.Address),
+		Type:              txType,
+		Data:              data,
+		PreviousPublicKey: hex.EncodeToString(transaction.PreviousPublicKey),
+		PreviousSignature: hex.EncodeToString(transaction.PreviousSignature),
+		OriginSignature:   hex.EncodeToString(transaction.OriginSignature),
+	}, nil
+}
+
+func (f TransactionFile) ToTransaction() (*archethic.TransactionBuilder, error) {
+	txType, ok := transactionTypeNames[f.Type]
+	if !ok {
+		return nil, errors.New("invalid transaction type: " + f.Type)
+	}
+	transaction := archethic.NewTransaction(txType)
+	transaction.Version = f.Version
+
+	var err error
+	if transaction.Address, err = hex.DecodeString(f.Address); err != nil {
+		return nil, err
+	}
+	if transaction.PreviousPublicKey, err = hex.DecodeString(f.PreviousPublicKey); err != nil {
+		return nil, err
+	}
+	if transaction.PreviousSignature, err = hex.DecodeString(f.PreviousSignature); err != nil {
+		return nil, err
+	}
+	if f.OriginSignature != "" {
+		if transaction.OriginSignature, err = hex.DecodeString(f.OriginSignature); err != nil {
+			return nil, err
+		}
+	}
+
+	content, err := hex.DecodeString(f.Data.Content)
+	if err != nil {
+		return nil, err
+	}
+	transaction.SetContent(content)
+	transaction.SetCode(f.Data.Code)
+
+	for _, t := range f.Data.Ledger.Uco {
+		to, err := hex.DecodeString(t.To)
+		if err != nil {
+			return nil, err
+		}
+		if t.Amount == nil {
+			return nil, errors.New("missing amount for UCO transfer to " + t.To)
+		}
+		transaction.AddUcoTransfer(to, t.Amount)
+	}
+	for _, t := range f.Data.Ledger.Token {
+		to, err := hex.DecodeString(t.To)
+		if err != nil {
+			return nil, err
+		}
+		tokenAddress, err := hex.DecodeString(t.TokenAddress)
+		if err != nil {
+			return nil, err
+		}
+		if t.Amount == nil {
+			return nil, errors.New("missing amount for token transfer to " + t.To)
+		}
+		transaction.AddTokenTransfer(to, tokenAddress, t.Amount, t.TokenID)
+	}
+	for _, o := range f.Data.Ownerships {
+		secret, err := hex.DecodeString(o.Secret)
+		if err != nil {
+			return nil, err
+		}
+		authorizedKeys := make([]archethic.AuthorizedKey, len(o.AuthorizedKeys))
+		for i, k := range o.AuthorizedKeys {
+			publicKey, err := hex.DecodeString(k.PublicKey)
+			if err != nil {
+				return nil, err
+			}
+			encryptedSecretKey, err := hex.DecodeString(k.EncryptedSecretKey)
+			if err != nil {
+				return nil, err
+			}
+			authorizedKeys[i] = archethic.AuthorizedKey{
+				PublicKey:          publicKey,
+				EncryptedSecretKey: encryptedSecretKey,
+			}
+		}
+		transaction.AddOwnership(secret, authorizedKeys)
+	}
+	for _, r := range f.Data.Recipients {
+		address, err := hex.DecodeString(r.Address)
+		if err != nil {
+			return nil, err
+		}
+		if r.Action == "" && r.Args == nil {
+			transaction.AddRecipient(address)
+		} else {
+			transaction.AddRecipientWithNamedAction(address, []byte(r.Action), r.Args)
+		}
+	}
+
+	return transaction, nil
+}
+
+// WriteTransactionFile writes the transaction as indented JSON, or to stdout if the path is empty
+func WriteTransactionFile(transaction *archethic.TransactionBuilder, path string) error {
+	file, err := NewTransactionFile(transaction)
+	if err != nil {
+		return err
+	}
+	fileBytes, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	fileBytes = append(fileBytes, '\n')
+	if path == "" {
+		_, err = os.Stdout.Write(fileBytes)
+		return err
+	}
+	return os.WriteFile(path, fileBytes, 0600)
+}
+
+func ReadTransactionFile(path string) (*archethic.TransactionBuilder, error) {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTransactionFile(fileBytes)
+}
+
+func ParseTransactionFile(fileBytes []byte) (*archethic.TransactionBuilder, error) {
+	// numbers are kept as json.Number so the recipients' args are serialized as they were signed
+	d := json.NewDecoder(strings.NewReader(string(fileBytes)))
+	d.UseNumber()
+	var file TransactionFile
+	if err := d.Decode(&file); err != nil {
+		return nil, err
+	}
+	return file.ToTransaction()
+}
@@ -0,0 +1,402 @@
+package tuiutils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// payloadReader reads the binary serialization of a transaction, as produced by libgo
+type payloadReader struct {
+	data []byte
+}
+
+func (r *payloadReader) read(n int) ([]byte, error) {
+	if n < 0 || len(r.data) < n {
+		return nil, errors.New("invalid transaction payload: unexpected end of data")
+	}
+	value := r.data[:n]
+	r.data = r.data[n:]
+	return value, nil
+}
+
+func (r *payloadReader) readByte() (byte, error) {
+	value, err := r.read(1)
+	if err != nil {
+		return 0, err
+	}
+	return value[0], nil
+}
+
+func (r *payloadReader) readUint32() (uint32, error) {
+	value, err := r.read(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(value), nil
+}
+
+func (r *payloadReader) readUint64() (uint64, error) {
+	value, err := r.read(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+func (r *payloadReader) readVarInt() (uint64, error) {
+	size, err := r.readByte()
+	if err != nil {
+		return 0, err
+	}
+	value, err := r.read(int(size))
+	if err != nil {
+		return 0, err
+	}
+	var result uint64
+	for _, b := range value {
+		result = (result << 8) + uint64(b)
+	}
+	return result, nil
+}
+
+func (r *payloadReader) readSizedContent32() ([]byte, error) {
+	size, err := r.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	return r.read(int(size))
+}
+
+func (r *payloadReader) readSizedContent8() ([]byte, error) {
+	size, err := r.readByte()
+	if err != nil {
+		return nil, err
+	}
+	return r.read(int(size))
+}
+
+func (r *payloadReader) readAddress() ([]byte, error) {
+	header, err := r.read(2)
+	if err != nil {
+		return nil, err
+	}
+	var hashSize int
+	switch archethic.HashAlgo(header[1]) {
+	case archethic.SHA256, archethic.SHA3_256:
+		hashSize = 32
+	case archethic.SHA512, archethic.SHA3_512, archethic.BLAKE2B:
+		hashSize = 64
+	default:
+		return nil, fmt.Errorf("invalid transaction payload: unknown hash algorithm %d", header[1])
+	}
+	hash, err := r.read(hashSize)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, header...), hash...), nil
+}
+
+func publicKeySize(curve archethic.Curve) (int, error) {
+	switch curve {
+	case archethic.ED25519:
+		return 32, nil
+	case archethic.P256, archethic.SECP256K1:
+		return 65, nil
+	default:
+		return 0, fmt.Errorf("invalid transaction payload: unknown curve %d", curve)
+	}
+}
+
+func (r *payloadReader) readPublicKey() ([]byte, error) {
+	header, err := r.read(2)
+	if err != nil {
+		return nil, err
+	}
+	keySize, err := publicKeySize(archethic.Curve(header[0]))
+	if err != nil {
+		return nil, err
+	}
+	key, err := r.read(keySize)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, header...), key...), nil
+}
+
+func (r *payloadReader) readOwnership() (archethic.Ownership, error) {
+	secret, err := r.readSizedContent32()
+	if err != nil {
+		return archethic.Ownership{}, err
+	}
+	nbKeys, err := r.readVarInt()
+	if err != nil {
+		return archethic.Ownership{}, err
+	}
+	authorizedKeys := make([]archethic.AuthorizedKey, nbKeys)
+	for i := range authorizedKeys {
+		publicKey, err := r.readPublicKey()
+		if err != nil {
+			return archethic.Ownership{}, err
+		}
+		// the encrypted secret key is the ephemeral public key, the authentication tag (16 bytes)
+		// and the encrypted 32 bytes AES key
+		ephemeralKeySize, err := publicKeySize(archethic.Curve(publicKey[0]))
+		if err != nil {
+			return archethic.Ownership{}, err
+		}
+		encryptedSecretKey, err := r.read(ephemeralKeySize + 16 + 32)
+		if err != nil {
+			return archethic.Ownership{}, err
+		}
+		authorizedKeys[i] = archethic.AuthorizedKey{
+			PublicKey:          publicKey,
+			EncryptedSecretKey: encryptedSecretKey,
+		}
+	}
+	return archethic.Ownership{Secret: secret, AuthorizedKeys: authorizedKeys}, nil
+}
+
+func (r *payloadReader) readRecipient(version uint32) (archethic.Recipient, error) {
+	if version == 1 {
+		address, err := r.readAddress()
+		return archethic.Recipient{Address: address}, err
+	}
+
+	kind, err := r.readByte()
+	if err != nil {
+		return archethic.Recipient{}, err
+	}
+	address, err := r.readAddress()
+	if err != nil {
+		return archethic.Recipient{}, err
+	}
+	if kind == 0 {
+		return archethic.Recipient{Address: address}, nil
+	}
+
+	action, err := r.readSizedContent8()
+	if err != nil {
+		return archethic.Recipient{}, err
+	}
+
+	var args []interface{}
+	if version == 2 {
+		size, err := r.readVarInt()
+		if err != nil {
+			return archethic.Recipient{}, err
+		}
+		argsJson, err := r.read(int(size))
+		if err != nil {
+			return archethic.Recipient{}, err
+		}
+		args, err = decodeArgsJson(argsJson)
+		if err != nil {
+			return archethic.Recipient{}, err
+		}
+	} else {
+		nbArgs, err := r.readByte()
+		if err != nil {
+			return archethic.Recipient{}, err
+		}
+		args = make([]interface{}, nbArgs)
+		for i := range args {
+			var rest []byte
+			args[i], rest, err = deserializeTypedData(r.data)
+			if err != nil {
+				return archethic.Recipient{}, err
+			}
+			r.data = rest
+		}
+	}
+	return archethic.Recipient{Address: address, Action: action, Args: args}, nil
+}
+
+// deserializeTypedData decodes a typed argument of a recipient with libgo, which doesn't check the bounds of the data:
+// a truncated or malformed argument is reported as an invalid payload instead of a panic
+func deserializeTypedData(data []byte) (value any, rest []byte, err error) {
+	if len(data) == 0 {
+		return nil, nil, errors.New("invalid transaction payload: unexpected end of data")
+	}
+	defer func() {
+		if recover() != nil {
+			value, rest, err = nil, nil, errors.New("invalid transaction payload: malformed recipient argument")
+		}
+	}()
+	value, rest, err = archethic.DeserializeTypedData(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid transaction payload: %w", err)
+	}
+	return value, rest, nil
+}
+
+// ParseTransactionPayload decodes the binary serialization of a transaction:
+// the origin signature payload, optionally followed by the size and the value of the origin signature
+func ParseTransactionPayload(payload []byte) (*archethic.TransactionBuilder, error) {
+	r := &payloadReader{data: payload}
+
+	version, err := r.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	address, err := r.readAddress()
+	if err != nil {
+		return nil, err
+	}
+	txType, err := r.readByte()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := archethic.TransactionType(txType).String(); err != nil {
+		return nil, err
+	}
+
+	transaction := archethic.NewTransaction(archethic.TransactionType(txType))
+	transaction.Version = version
+	transaction.Address = address
+
+	code, err := r.readSizedContent32()
+	if err != nil {
+		return nil, err
+	}
+	transaction.SetCode(string(code))
+
+	content, err := r.readSizedContent32()
+	if err != nil {
+		return nil, err
+	}
+	transaction.SetContent(content)
+
+	nbOwnerships, err := r.readVarInt()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < nbOwnerships; i++ {
+		ownership, err := r.readOwnership()
+		if err != nil {
+			return nil, err
+		}
+		transaction.Data.Ownerships = append(transaction.Data.Ownerships, ownership)
+	}
+
+	nbUcoTransfers, err := r.readVarInt()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < nbUcoTransfers; i++ {
+		to, err := r.readAddress()
+		if err != nil {
+			return nil, err
+		}
+		amount, err := r.readUint64()
+		if err != nil {
+			return nil, err
+		}
+		transaction.AddUcoTransfer(to, new(big.Int).SetUint64(amount))
+	}
+
+	nbTokenTransfers, err := r.readVarInt()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < nbTokenTransfers; i++ {
+		tokenAddress, err := r.readAddress()
+		if err != nil {
+			return nil, err
+		}
+		to, err := r.readAddress()
+		if err != nil {
+			return nil, err
+		}
+		amount, err := r.readUint64()
+		if err != nil {
+			return nil, err
+		}
+		tokenId, err := r.readVarInt()
+		if err != nil {
+			return nil, err
+		}
+		transaction.AddTokenTransfer(to, tokenAddress, new(big.Int).SetUint64(amount), uint(tokenId))
+	}
+
+	nbRecipients, err := r.readVarInt()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < nbRecipients; i++ {
+		recipient, err := r.readRecipient(version)
+		if err != nil {
+			return nil, err
+		}
+		transaction.Data.Recipients = append(transaction.Data.Recipients, recipient)
+	}
+
+	// an unsigned payload stops here
+	if len(r.data) == 0 {
+		return transaction, nil
+	}
+
+	if transaction.PreviousPublicKey, err = r.readPublicKey(); err != nil {
+		return nil, err
+	}
+	if transaction.PreviousSignature, err = r.readSizedContent8(); err != nil {
+		return nil, err
+	}
+	if len(r.data) > 0 {
+		if transaction.OriginSignature, err = r.readSizedContent8(); err != nil {
+			return nil, err
+		}
+	}
+	if len(r.data) > 0 {
+		return nil, errors.New("invalid transaction payload: unexpected trailing data")
+	}
+	return transaction, nil
+}
+
+// SerializeTransaction returns the binary serialization of a transaction read by ParseTransactionPayload
+func SerializeTransaction(transaction *archethic.TransactionBuilder) []byte {
+	payload := transaction.OriginSignaturePayload()
+	if transaction.PreviousPublicKey == nil {
+		// an unsigned payload stops before the empty previous signature
+		return payload[:len(payload)-1]
+	}
+	if transaction.OriginSignature != nil {
+		payload = append(payload, byte(len(transaction.OriginSignature)))
+		payload = append(payload, transaction.OriginSignature...)
+	}
+	return payload
+}
+
+// VerifyPreviousSignature checks the previous signature of the transaction against its previous public key
+func VerifyPreviousSignature(transaction *archethic.TransactionBuilder) (bool, error) {
+	if len(transaction.PreviousPublicKey) < 2 || len(transaction.PreviousSignature) == 0 {
+		return false, errors.New("transaction is not signed")
+	}
+	keySize, err := publicKeySize(archethic.Curve(transaction.PreviousPublicKey[0]))
+	if err != nil {
+		return false, err
+	}
+	if len(transaction.PreviousPublicKey) != keySize+2 {
+		return false, errors.New("invalid previous public key size")
+	}
+	// the origin signature payload is the previous signature payload followed by
+	// the previous public key and the size and value of the previous signature
+	payload := transaction.OriginSignaturePayload()
+	previousSignaturePayload := payload[:len(payload)-len(transaction.PreviousPublicKey)-1-len(transaction.PreviousSignature)]
+	return archethic.Verify(transaction.PreviousSignature, previousSignaturePayload, transaction.PreviousPublicKey)
+}
+
+func decodeArgsJson(argsJson []byte) ([]interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(argsJson))
+	d.UseNumber()
+	var args []interface{}
+	if err := d.Decode(&args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
@@ -0,0 +1,170 @@
+package tuiutils
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+func testTransaction(t *testing.T, version uint32) *archethic.TransactionBuilder {
+	t.Helper()
+	address, err := archethic.DeriveAddress([]byte("recipient"), 0, archethic.ED25519, archethic.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenAddress, err := archethic.DeriveAddress([]byte("token"), 0, archethic.P256, archethic.SHA3_512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, _, err := archethic.DeriveKeypair([]byte("authorized"), 0, archethic.SECP256K1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryptedSecretKey, err := archethic.EcEncrypt(make([]byte, 32), publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transaction := archethic.NewTransaction(archethic.TransferType)
+	transaction.Version = version
+	transaction.SetAddress(address)
+	transaction.SetCode("condition inherit: []")
+	transaction.SetContent([]byte("content"))
+	transaction.AddOwnership([]byte("secret"), []archethic.AuthorizedKey{{PublicKey: publicKey, EncryptedSecretKey: encryptedSecretKey}})
+	transaction.AddUcoTransfer(address, big.NewInt(100_000_000))
+	transaction.AddTokenTransfer(address, tokenAddress, big.NewInt(42), 1)
+	transaction.AddRecipient(address)
+	if version > 1 {
+		transaction.AddRecipientWithNamedAction(address, []byte("vote"), []interface{}{"yes", map[string]interface{}{"weight": "2"}})
+	}
+	return transaction
+}
+
+func signTestTransaction(t *testing.T, transaction *archethic.TransactionBuilder, originSign bool) {
+	t.Helper()
+	seed := []byte("seed")
+	if err := transaction.Build(seed, 0, archethic.ED25519, archethic.SHA256); err != nil {
+		t.Fatal(err)
+	}
+	if !originSign {
+		return
+	}
+	_, originPrivateKey, err := archethic.DeriveKeypair(seed, 0, archethic.ED25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := transaction.OriginSign(originPrivateKey); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTransactionPayloadRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    uint32
+		sign       bool
+		originSign bool
+	}{
+		{"version 1 unsigned", 1, false, false},
+		{"version 1 signed", 1, true, true},
+		{"version 2 unsigned", 2, false, false},
+		{"version 2 signed", 2, true, true},
+		{"version 3 unsigned", 3, false, false},
+		{"version 3 without origin signature", 3, true, false},
+		{"version 3 signed", 3, true, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transaction := testTransaction(t, test.version)
+			if test.sign {
+				signTestTransaction(t, transaction, test.originSign)
+			}
+			payload := SerializeTransaction(transaction)
+
+			parsed, err := ParseTransactionPayload(payload)
+			if err != nil {
+				t.Fatalf("ParseTransactionPayload: %s", err)
+			}
+			if parsed.Version != test.version {
+				t.Errorf("version = %d, want %d", parsed.Version, test.version)
+			}
+			if len(parsed.Data.Recipients) != len(transaction.Data.Recipients) {
+				t.Fatalf("%d recipients, want %d", len(parsed.Data.Recipients), len(transaction.Data.Recipients))
+			}
+			if test.version > 1 && string(parsed.Data.Recipients[1].Action) != "vote" {
+				t.Errorf("recipient action = %q, want %q", parsed.Data.Recipients[1].Action, "vote")
+			}
+			if !bytes.Equal(parsed.OriginSignature, transaction.OriginSignature) {
+				t.Errorf("origin signature = %x, want %x", parsed.OriginSignature, transaction.OriginSignature)
+			}
+			if reserialized := SerializeTransaction(parsed); !bytes.Equal(reserialized, payload) {
+				t.Errorf("serialized payload = %x, want %x", reserialized, payload)
+			}
+			if test.sign {
+				valid, err := VerifyPreviousSignature(parsed)
+				if err != nil || !valid {
+					t.Errorf("VerifyPreviousSignature = %t, %v, want true", valid, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTransactionPayloadTruncated(t *testing.T) {
+	for _, version := range []uint32{1, 2, 3} {
+		unsigned := SerializeTransaction(testTransaction(t, version))
+		transaction := testTransaction(t, version)
+		signTestTransaction(t, transaction, false)
+		withoutOrigin := SerializeTransaction(transaction)
+		signTestTransaction(t, transaction, true)
+		payload := SerializeTransaction(transaction)
+
+		// the unsigned payload and the payload without origin signature are the only valid prefixes
+		for n := 0; n < len(payload); n++ {
+			_, err := ParseTransactionPayload(payload[:n])
+			valid := n == len(unsigned) || n == len(withoutOrigin)
+			if valid && err != nil {
+				t.Errorf("version %d, %d bytes: %s", version, n, err)
+			}
+			if !valid && err == nil {
+				t.Errorf("version %d, %d bytes: truncated payload accepted", version, n)
+			}
+		}
+	}
+}
+
+func TestParseTransactionPayloadMalformed(t *testing.T) {
+	payload := SerializeTransaction(testTransaction(t, 3))
+	// the transaction type follows the version and the address
+	unknownType := append([]byte{}, payload...)
+	unknownType[4+34] = 0xee
+	unknownHash := append([]byte{}, payload...)
+	unknownHash[5] = 0xee
+
+	// a named action without arguments ends the payload with the number of arguments
+	transaction := testTransaction(t, 3)
+	transaction.AddRecipientWithNamedAction(transaction.Data.Recipients[0].Address, []byte("close"), []interface{}{})
+	noArgs := SerializeTransaction(transaction)
+	unknownArgType := append(append([]byte{}, noArgs[:len(noArgs)-1]...), 1, 0xee)
+
+	tests := []struct {
+		name    string
+		payload []byte
+	}{
+		{"empty", nil},
+		{"unknown transaction type", unknownType},
+		{"unknown hash algorithm", unknownHash},
+		{"trailing data", append(append([]byte{}, payload...), 0, 1, 2)},
+		{"unknown argument type", unknownArgType},
+		{"truncated argument", append(append([]byte{}, noArgs[:len(noArgs)-1]...), 1)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseTransactionPayload(test.payload); err == nil {
+				t.Error("malformed payload accepted")
+			}
+		})
+	}
+}
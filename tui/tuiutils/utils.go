@@ -7,7 +7,6 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -19,13 +18,8 @@ import (
 
 	archethic "github.com/archethic-foundation/libgo"
 	"github.com/spf13/pflag"
-	"github.com/tyler-smith/go-bip39"
-	"github.com/tyler-smith/go-bip39/wordlists"
-	"github.com/ybbus/jsonrpc/v3"
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/terminal"
-	"golang.org/x/text/unicode/norm"
-	"gopkg.in/yaml.v3"
 )
 
 func GetHashAlgorithmName(h archethic.HashAlgo) string {
@@ -56,10 +50,10 @@ func GetCurveName(h archethic.Curve) string {
 	panic("Unknown curve")
 }
 
-func CreateKeychain(url string, accessSeed []byte) (string, string, string, string, error) {
+func CreateKeychain(url string, accessSigner Signer) (string, string, string, string, error) {
 	originPrivateKey, _ := hex.DecodeString("01019280BDB84B8F8AEDBA205FE3552689964A5626EE2C60AA10E3BF22A91A036009")
 
-	publicKey, _, err := archethic.DeriveKeypair(accessSeed, 0, archethic.ED25519)
+	publicKey, err := accessSigner.PublicKey(archethic.ED25519, 0)
 	if err != nil {
 		return "", "", "", "", err
 	}
@@ -71,7 +65,7 @@ func CreateKeychain(url string, accessSeed []byte) (string, string, string, stri
 	keychain.AddService("uco", "m/650'/0", archethic.ED25519, archethic.SHA256)
 	keychain.AddAuthorizedPublicKey(publicKey)
 
-	accessAddress, err := archethic.DeriveAddress(accessSeed, 1, archethic.ED25519, archethic.SHA256)
+	accessAddress, err := SignerAddress(accessSigner, 1, archethic.ED25519, archethic.SHA256)
 	if err != nil {
 		return "", "", "", "", err
 	}
@@ -87,72 +81,53 @@ func CreateKeychain(url string, accessSeed []byte) (string, string, string, stri
 	keychainTx.OriginSign(originPrivateKey)
 
 	client := archethic.NewAPIClient(url)
-	accessKeychain, _ := archethic.GetKeychain(accessSeed, *client)
+	accessKeychain, _ := GetKeychain(accessSigner, *client)
 	if accessKeychain != nil {
 		err = errors.New("keychain access already exists")
 		return "", "", "", "", err
 	}
 
-	var returnedError error
-	feedback := ""
-	keychainSeed := ""
-	keychainTransactionAddress := ""
-	keychainAccessTransactionAddress := ""
-	ts := archethic.NewTransactionSender(client)
-	ts.AddOnRequiredConfirmation(func(nbConf uint) {
-		feedback += "\nKeychain's transaction confirmed."
-
-		keychainSeed = hex.EncodeToString(randomSeed)
-		keychainTransactionAddress = fmt.Sprintf("%s/explorer/transaction/%x", url, keychainAddress)
+	if _, err := sendTransactionAndWait(url, keychainTx, AllConfirmations, DefaultTimeout); err != nil {
+		return "\n" + DescribeError(err), "", "", "", err
+	}
+	feedback := "\nKeychain's transaction confirmed."
+	keychainSeed := hex.EncodeToString(randomSeed)
+	keychainTransactionAddress := fmt.Sprintf("%s/explorer/transaction/%x", url, keychainAddress)
 
-		accessTx, err := archethic.NewAccessTransaction(accessSeed, keychainAddress)
-		if err != nil {
-			returnedError = err
-			feedback = err.Error()
-		}
-		accessTx.OriginSign(originPrivateKey)
-		ts2 := archethic.NewTransactionSender(client)
-		ts2.AddOnRequiredConfirmation(func(nbConf uint) {
-			feedback += "\nKeychain access transaction confirmed."
-			ts2.Unsubscribe("confirmation")
-			keychainAccessTransactionAddress = fmt.Sprintf("%s/explorer/transaction/%x", url, accessAddress)
-		})
-		ts2.AddOnError(func(context string, error archethic.ErrorDetails) {
-			feedback += handleTransactionError(context, error).Error()
-			ts.Unsubscribe("error")
-		})
-		ts2.SendTransaction(accessTx, 100, 60)
-		ts.Unsubscribe("confirmation")
-	})
-	ts.AddOnError(func(context string, error archethic.ErrorDetails) {
-		returnedError = handleTransactionError(context, error)
-		feedback += returnedError.Error()
-		ts.Unsubscribe("error")
-	})
-	ts.SendTransaction(keychainTx, 100, 60)
-	return feedback, keychainSeed, keychainTransactionAddress, keychainAccessTransactionAddress, returnedError
+	accessTx, err := NewAccessTransaction(accessSigner, keychainAddress)
+	if err != nil {
+		return err.Error(), keychainSeed, keychainTransactionAddress, "", err
+	}
+	accessTx.OriginSign(originPrivateKey)
+	// the keychain is created even if its access transaction fails, so its seed is returned with the error
+	if _, err := sendTransactionAndWait(url, accessTx, AllConfirmations, DefaultTimeout); err != nil {
+		return feedback, keychainSeed, keychainTransactionAddress, "", err
+	}
+	feedback += "\nKeychain access transaction confirmed."
+	keychainAccessTransactionAddress := fmt.Sprintf("%s/explorer/transaction/%x", url, accessAddress)
+	return feedback, keychainSeed, keychainTransactionAddress, keychainAccessTransactionAddress, nil
 }
 
-func AccessKeychain(endpoint string, seed []byte) (*archethic.Keychain, error) {
+func AccessKeychain(endpoint string, signer Signer) (*archethic.Keychain, error) {
 	client := archethic.NewAPIClient(endpoint)
-	return archethic.GetKeychain(seed, *client)
+	return GetKeychain(signer, *client)
 }
 
-func AddServiceToKeychain(accessSeed []byte, endpoint string, serviceName string, serviceDerivationPath string) (string, error) {
-	return updateKeychain(accessSeed, endpoint, func(keychain *archethic.Keychain) {
+func AddServiceToKeychain(accessSigner Signer, endpoint string, serviceName string, serviceDerivationPath string, waitConfirmations uint, timeout uint) (string, error) {
+	return updateKeychain(accessSigner, endpoint, waitConfirmations, timeout, func(keychain *archethic.Keychain) {
 		keychain.AddService(serviceName, serviceDerivationPath, archethic.ED25519, archethic.SHA256)
 	})
 }
 
-func RemoveServiceFromKeychain(accessSeed []byte, endpoint string, serviceName string) (string, error) {
-	return updateKeychain(accessSeed, endpoint, func(keychain *archethic.Keychain) {
+func RemoveServiceFromKeychain(accessSigner Signer, endpoint string, serviceName string, waitConfirmations uint, timeout uint) (string, error) {
+	return updateKeychain(accessSigner, endpoint, waitConfirmations, timeout, func(keychain *archethic.Keychain) {
 		keychain.RemoveService(serviceName)
 	})
 }
 
-func updateKeychain(accessSeed []byte, endpoint string, updateFunc func(*archethic.Keychain)) (string, error) {
+func updateKeychain(accessSigner Signer, endpoint string, waitConfirmations uint, timeout uint, updateFunc func(*archethic.Keychain)) (string, error) {
 	client := *archethic.NewAPIClient(endpoint)
-	keychain, err := archethic.GetKeychain(accessSeed, client)
+	keychain, err := GetKeychain(accessSigner, client)
 	if err != nil {
 		return "", err
 	}
@@ -171,57 +146,51 @@ func updateKeychain(accessSeed []byte, endpoint string, updateFunc func(*archeth
 	originPrivateKey, _ := hex.DecodeString("01019280BDB84B8F8AEDBA205FE3552689964A5626EE2C60AA10E3BF22A91A036009")
 	transaction.OriginSign(originPrivateKey)
 
-	var returnedError error
-	returnedFeedback := ""
-	returnedError = nil
-
-	ts := archethic.NewTransactionSender(&client)
-	ts.AddOnRequiredConfirmation(func(nbConf uint) {
-		returnedFeedback = "\nKeychain's transaction confirmed."
-	})
-	ts.AddOnError(func(context string, error archethic.ErrorDetails) {
-		returnedError = handleTransactionError(context, error)
-		ts.Unsubscribe("error")
-	})
-	ts.SendTransaction(transaction, 100, 60)
-
-	return returnedFeedback, returnedError
-}
-
-func SendTransaction(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, transactionIndex uint, serviceName string, storageNouncePublicKey string, seed []byte) (string, error) {
-	err := buildTransactionToSend(transaction, secretKey, curve, serviceMode, endpoint, transactionIndex, serviceName, storageNouncePublicKey, seed)
+	_, err = sendTransactionAndWait(endpoint, transaction, waitConfirmations, timeout)
 	if err != nil {
 		return "", err
 	}
-	feedback := ""
-	client := archethic.NewAPIClient(endpoint)
-	ts := archethic.NewTransactionSender(client)
-	ts.AddOnSent(func() {
-		feedback = endpoint + "/explorer/transaction/" + strings.ToUpper(hex.EncodeToString(transaction.Address))
-	})
-
-	ts.AddOnError(func(context string, error archethic.ErrorDetails) {
-		feedback = handleTransactionError(context, error).Error()
-	})
-
-	ts.SendTransaction(transaction, 100, 60)
-	return feedback, nil
+	if waitConfirmations == 0 {
+		return "\nKeychain's transaction sent.", nil
+	}
+	return "\nKeychain's transaction confirmed.", nil
 }
 
-func GetTransactionFeeJson(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, transactionIndex uint, serviceName string, storageNouncePublicKey string, seed []byte) (string, error) {
-	fee, err := GetTransactionFee(transaction, secretKey, curve, serviceMode, endpoint, transactionIndex, serviceName, storageNouncePublicKey, seed)
+// SendTransaction builds the transaction, runs the checks (see TransactionChecks) and sends it
+func SendTransaction(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, transactionIndex uint, serviceName string, storageNouncePublicKey string, signer Signer, checks TransactionChecks, waitConfirmations uint, timeout uint) (string, error) {
+	err := buildTransactionToSend(transaction, secretKey, curve, serviceMode, endpoint, transactionIndex, serviceName, storageNouncePublicKey, signer)
 	if err != nil {
 		return "", err
 	}
-	feeBytes, err := json.Marshal(fee)
+	if _, err := CheckTransaction(transaction, endpoint, checks); err != nil {
+		return "", err
+	}
+	return BroadcastTransaction(transaction, endpoint, waitConfirmations, timeout)
+}
+
+// SignTransaction builds and signs the transaction without any network access,
+// so it can be used on an offline host and broadcasted later with BroadcastTransaction
+func SignTransaction(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, transactionIndex uint, storageNouncePublicKey string, signer Signer) error {
+	return buildTransactionToSend(transaction, secretKey, curve, false, "", transactionIndex, "", storageNouncePublicKey, signer)
+}
+
+// BroadcastTransaction sends an already signed transaction to the given endpoint
+// and waits for the given number of confirmations (0 to only wait until it is sent)
+func BroadcastTransaction(transaction *archethic.TransactionBuilder, endpoint string, waitConfirmations uint, timeout uint) (string, error) {
+	explorerUrl := endpoint + "/explorer/transaction/" + strings.ToUpper(hex.EncodeToString(transaction.Address))
+	_, err := sendTransactionAndWait(endpoint, transaction, waitConfirmations, timeout)
 	if err != nil {
+		if sendError, ok := err.(SendTransactionError); ok && sendError.Status == TransactionUnconfirmed {
+			sendError.Err = fmt.Errorf("%w (%s)", sendError.Err, explorerUrl)
+			return "", sendError
+		}
 		return "", err
 	}
-	return string(feeBytes), nil
+	return explorerUrl, nil
 }
 
-func GetTransactionFee(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, transactionIndex uint, serviceName string, storageNouncePublicKey string, seed []byte) (archethic.Fee, error) {
-	err := buildTransactionToSend(transaction, secretKey, curve, serviceMode, endpoint, transactionIndex, serviceName, storageNouncePublicKey, seed)
+func GetTransactionFee(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, transactionIndex uint, serviceName string, storageNouncePublicKey string, signer Signer) (archethic.Fee, error) {
+	err := buildTransactionToSend(transaction, secretKey, curve, serviceMode, endpoint, transactionIndex, serviceName, storageNouncePublicKey, signer)
 	if err != nil {
 		return archethic.Fee{}, err
 	}
@@ -233,48 +202,31 @@ func GetTransactionFee(transaction *archethic.TransactionBuilder, secretKey []by
 	return fee, nil
 }
 
-func buildTransactionToSend(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, transactionIndex uint, serviceName string, storageNouncePublicKey string, seed []byte) error {
-	if len(transaction.Data.Code) > 0 {
-		ownershipIndex := -1
-		for i, ownership := range transaction.Data.Ownerships {
-			decryptSecret, err := archethic.AesDecrypt(ownership.Secret, secretKey)
-			if err != nil {
-				return err
-			}
-			decodedSecret := string(decryptSecret)
-
-			if reflect.DeepEqual(decodedSecret, string(seed)) {
-				ownershipIndex = i
-				break
-			}
+func buildTransactionToSend(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, transactionIndex uint, serviceName string, storageNouncePublicKey string, signer Signer) error {
+	if serviceMode {
+		client := archethic.NewAPIClient(endpoint)
+		keychain, err := GetKeychain(signer, *client)
+		if err != nil {
+			return err
 		}
-
-		if ownershipIndex == -1 {
-			return errors.New("you need to create an ownership with the transaction seed as secret and authorize node public key to let nodes generate new transaction from your smart contract")
-		} else {
-			authorizedKeyIndex := -1
-			for i, authKey := range transaction.Data.Ownerships[ownershipIndex].AuthorizedKeys {
-				if reflect.DeepEqual(strings.ToUpper(hex.EncodeToString(authKey.PublicKey)), storageNouncePublicKey) {
-					authorizedKeyIndex = i
-					break
-				}
-			}
-
-			if authorizedKeyIndex == -1 {
-				return errors.New("you need to create an ownership with the transaction seed as secret and authorize node public key to let nodes generate new transaction from your smart contract")
-			}
+		err = checkKeychainSmartContractOwnership(transaction, secretKey, keychain, serviceName, storageNouncePublicKey)
+		if err != nil {
+			return err
 		}
-	}
-
-	client := archethic.NewAPIClient(endpoint)
-
-	if serviceMode {
-		err := buildKeychainTransaction(seed, client, transaction, serviceName)
+		err = buildKeychainTransaction(keychain, client, transaction, serviceName)
 		if err != nil {
 			return err
 		}
 	} else {
-		err := transaction.Build(seed, uint32(transactionIndex), curve, archethic.SHA256)
+		seed, ok := signer.(SeedSigner)
+		if !ok && len(transaction.Data.Code) > 0 {
+			return ErrSignerWithoutSeed
+		}
+		err := checkSmartContractOwnership(transaction, secretKey, storageNouncePublicKey, seed)
+		if err != nil {
+			return err
+		}
+		err = BuildTransaction(transaction, signer, uint32(transactionIndex), curve, archethic.SHA256)
 		if err != nil {
 			return err
 		}
@@ -285,12 +237,54 @@ func buildTransactionToSend(transaction *archethic.TransactionBuilder, secretKey
 	return nil
 }
 
-func buildKeychainTransaction(seed []byte, client *archethic.APIClient, transaction *archethic.TransactionBuilder, serviceName string) error {
-	keychain, err := archethic.GetKeychain(seed, *client)
+// SignKeychainTransaction signs the transaction for a service of an already fetched keychain,
+// with an explicit chain index instead of the last one known by the network
+func SignKeychainTransaction(transaction *archethic.TransactionBuilder, secretKey []byte, keychain *archethic.Keychain, serviceName string, transactionIndex uint, storageNouncePublicKey string) error {
+	err := checkKeychainSmartContractOwnership(transaction, secretKey, keychain, serviceName, storageNouncePublicKey)
 	if err != nil {
 		return err
 	}
 
+	err = keychain.BuildTransaction(transaction, serviceName, uint8(transactionIndex))
+	if err != nil {
+		return err
+	}
+
+	originPrivateKey, _ := hex.DecodeString("01019280BDB84B8F8AEDBA205FE3552689964A5626EE2C60AA10E3BF22A91A036009")
+	transaction.OriginSign(originPrivateKey)
+	return nil
+}
+
+// checkSmartContractOwnership checks that the nodes will be able to generate the next transactions
+// of the smart contract, from the seed of the chain (see AddContractOwnership)
+func checkSmartContractOwnership(transaction *archethic.TransactionBuilder, secretKey []byte, storageNouncePublicKey string, chainSeed []byte) error {
+	if len(transaction.Data.Code) == 0 {
+		return nil
+	}
+	found, err := hasContractOwnership(transaction, secretKey, storageNouncePublicKey, chainSeed)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("you need to create an ownership with the transaction seed as secret and authorize node public key to let nodes generate new transaction from your smart contract")
+	}
+	return nil
+}
+
+// checkKeychainSmartContractOwnership checks the smart contract ownership of a transaction of a keychain service,
+// whose chain seed is derived from the keychain seed
+func checkKeychainSmartContractOwnership(transaction *archethic.TransactionBuilder, secretKey []byte, keychain *archethic.Keychain, serviceName string, storageNouncePublicKey string) error {
+	if len(transaction.Data.Code) == 0 {
+		return nil
+	}
+	chainSeed, err := KeychainServiceSeed(keychain, serviceName)
+	if err != nil {
+		return err
+	}
+	return checkSmartContractOwnership(transaction, secretKey, storageNouncePublicKey, chainSeed)
+}
+
+func buildKeychainTransaction(keychain *archethic.Keychain, client *archethic.APIClient, transaction *archethic.TransactionBuilder, serviceName string) error {
 	genesisAddress, err := keychain.DeriveAddress(serviceName, 0)
 	if err != nil {
 		return err
@@ -338,13 +332,14 @@ func GetSSHPrivateKey(privateKeyPath string) ([]byte, error) {
 	return pvKeyBytes, nil
 }
 
+// promptSecret prompts on the standard error, the standard output is kept for the result of the commands
 func promptSecret(message string) string {
-	fmt.Printf(message)
+	fmt.Fprint(os.Stderr, message)
 	passphrase, err := terminal.ReadPassword(int(syscall.Stdin))
 	if err != nil {
 		log.Fatalf("Failed to read secret: %v", err)
 	}
-	fmt.Println()
+	fmt.Fprintln(os.Stderr)
 	return string(passphrase)
 }
 
@@ -360,13 +355,21 @@ func GetLastTransactionIndex(url string, curve archethic.Curve, seed []byte) (ui
 }
 
 func GetSeedBytes(flags *pflag.FlagSet, sshFlagKey, sshPathFlagKey, seedFlagKey, mnemonicFlag string) ([]byte, error) {
+	// if the keystore flag (shared by all the commands) is set, decrypt the seed of the keystore entry
+	if flags.Lookup(KeystoreFlag) != nil && flags.Changed(KeystoreFlag) {
+		name, _ := flags.GetString(KeystoreFlag)
+		return GetKeystoreSeed(name)
+	}
+	mnemonicOptions, err := GetMnemonicOptions(flags)
+	if err != nil {
+		return nil, err
+	}
 	// if the mnemonic flag is set, get the mnemonic words with a prompt
 	if mnemonicFlag != "" {
 		mnemonic, _ := flags.GetBool(mnemonicFlag)
 		if mnemonic {
 			words := promptSecret("Enter mnemonic words:")
-			var err error
-			accessSeedBytes, err := ExtractSeedFromMnemonic(words)
+			accessSeedBytes, err := ExtractSeedFromMnemonic(words, mnemonicOptions)
 			if err != nil {
 				return nil, err
 			}
@@ -404,103 +407,7 @@ func GetSeedBytes(flags *pflag.FlagSet, sshFlagKey, sshPathFlagKey, seedFlagKey,
 		return nil, nil
 	}
 
-	// otherwise try to get the seed from the seedFlagKey
+	// otherwise try to get the seed, or its secret reference, from the seedFlagKey
 	accessSeed, _ := flags.GetString(seedFlagKey)
-	// check if the provided seed looks like a mnemonic word list
-	potentialWordsList := strings.Fields(accessSeed)
-	if len(potentialWordsList) == 24 {
-		seed, err := ExtractSeedFromMnemonic(accessSeed)
-		if err == nil && seed != nil {
-			return seed, nil
-		}
-	}
-
-	return archethic.MaybeConvertToHex(accessSeed)
-}
-
-func ExtractSeedFromMnemonic(words string) ([]byte, error) {
-	// check if it's a bip39 word list in English
-	if bip39.IsMnemonicValid(words) {
-		seed, err := bip39.EntropyFromMnemonic(words)
-		if err != nil {
-			return nil, err
-		}
-		return seed, nil
-	}
-	// check if it's a bip39 word list in French
-	bip39.SetWordList(wordlists.French)
-	// normalize the string to NFD (as the bip39 French word list is in NFD)
-	nfd := norm.NFD.String(words)
-	if bip39.IsMnemonicValid(nfd) {
-		seed, err := bip39.EntropyFromMnemonic(nfd)
-		if err != nil {
-			return nil, err
-		}
-		return seed, nil
-	}
-	return nil, nil
-}
-
-type customError struct {
-	Error   string `yaml:"Error"`
-	Message string `yaml:"Message,omitempty"`
-	Data    any    `yaml:"Data,omitempty"`
-}
-
-func customErrorDetails(context string, error archethic.ErrorDetails) string {
-	var message string
-
-	var customErr customError
-	message = fmt.Sprintf("%s (%d)", error.Message, error.Code)
-
-	customErr = customError{
-		Error:   context,
-		Message: message,
-	}
-
-	if error.Data != nil {
-		customDataDetails, _ := yaml.Marshal(error.Data)
-		customErr.Data = fmt.Sprintf("%s", customDataDetails)
-	}
-
-	b, _ := yaml.Marshal(customErr)
-
-	return fmt.Sprintf("\n%s", b)
-}
-
-func handleTransactionError(context string, err error) error {
-	if errorDetails, ok := err.(archethic.ErrorDetails); ok {
-		return errors.New(customErrorDetails(context, errorDetails))
-	}
-
-	if jsonRpcError, ok := err.(*jsonrpc.RPCError); ok {
-		if mapError, ok := jsonRpcError.Data.(map[string]interface{}); ok {
-			errorMessage := fmt.Sprintf("Error %d: %s %s.", jsonRpcError.Code, jsonRpcError.Message, flattenNestedMap(mapError, ""))
-			return errors.New(errorMessage)
-		}
-	}
-
-	return err
-}
-
-func flattenNestedMap(nestedMap map[string]interface{}, prefix string) string {
-	var results string
-
-	for key, value := range nestedMap {
-		if innerMap, ok := value.(map[string]interface{}); ok {
-			results += flattenNestedMap(innerMap, fmt.Sprintf("%s%s.", prefix, key))
-		} else if arr, ok := value.([]interface{}); ok {
-			for i, item := range arr {
-				if innerMap, ok := item.(map[string]interface{}); ok {
-					results += flattenNestedMap(innerMap, fmt.Sprintf("%s%s[%d].", prefix, key, i))
-				} else {
-					results += fmt.Sprintf("%s%s[%d] => %v\n", prefix, key, i, item)
-				}
-			}
-		} else {
-			results += fmt.Sprintf("%s%s => %v\n", prefix, key, value)
-		}
-	}
-
-	return results
+	return ParseSeed(accessSeed, mnemonicOptions)
 }
@@ -0,0 +1,252 @@
+package tuiutils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+const (
+	// MaxContentSize is the maximum size in bytes of the content of a transaction accepted by the nodes
+	MaxContentSize = 3_145_728
+	// WebsiteVersion is the version of the hosting format (aeweb) of the websites
+	WebsiteVersion = 1
+)
+
+// WebsiteManifest is the content of the reference transaction of a website: the files of the website,
+// each one stored in the content of one or several hosting transactions of the same chain
+type WebsiteManifest struct {
+	AewebVersion   int                        `json:"aewebVersion"`
+	HashFunction   string                     `json:"hashFunction"`
+	MetaData       map[string]WebsiteFileMeta `json:"metaData"`
+	SslCertificate string                     `json:"sslCertificate,omitempty"`
+}
+
+// WebsiteFileMeta describes a file of a website: the hash of its raw data, the size of its encoded data
+// and the addresses of the transactions holding the parts of the encoded data, in order
+type WebsiteFileMeta struct {
+	Encoding  string   `json:"encoding"`
+	Hash      string   `json:"hash"`
+	Size      int      `json:"size"`
+	Addresses []string `json:"addresses"`
+}
+
+// WebsiteFile is a file of a website to upload
+type WebsiteFile struct {
+	// Path is the path of the file in the website, with / as separator and without leading /
+	Path string
+	Hash string
+	// Data is the gzipped data of the file, encoded in base64url
+	Data string
+}
+
+// WebsitePart is a part of the encoded data of a file, stored in a hosting transaction
+type WebsitePart struct {
+	Path string
+	Data string
+}
+
+// NewWebsiteManifest returns an empty manifest
+func NewWebsiteManifest() WebsiteManifest {
+	return WebsiteManifest{
+		AewebVersion: WebsiteVersion,
+		HashFunction: "sha1",
+		MetaData:     map[string]WebsiteFileMeta{},
+	}
+}
+
+// EncodeWebsiteFile hashes and encodes the data of a file as expected by the nodes serving the websites
+func EncodeWebsiteFile(path string, data []byte) (WebsiteFile, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(data); err != nil {
+		return WebsiteFile{}, err
+	}
+	if err := writer.Close(); err != nil {
+		return WebsiteFile{}, err
+	}
+	hash := sha1.Sum(data)
+	return WebsiteFile{
+		Path: path,
+		Hash: hex.EncodeToString(hash[:]),
+		Data: base64.RawURLEncoding.EncodeToString(buffer.Bytes()),
+	}, nil
+}
+
+// PackWebsiteFiles splits the files into the contents of the hosting transactions:
+// the small files are grouped, the large ones are split across several transactions
+func PackWebsiteFiles(files []WebsiteFile) ([][]WebsitePart, error) {
+	var contents [][]WebsitePart
+	var current []WebsitePart
+	// the size of the JSON object {"path":"data",...} of the current content
+	currentSize := 2
+	flush := func() {
+		contents = append(contents, current)
+		current = nil
+		currentSize = 2
+	}
+
+	for _, file := range files {
+		pathBytes, err := json.Marshal(file.Path)
+		if err != nil {
+			return nil, err
+		}
+		// "path":"data",
+		overhead := len(pathBytes) + 4
+		if 2+overhead >= MaxContentSize {
+			return nil, fmt.Errorf("the path of the file %s is too long", file.Path)
+		}
+
+		data := file.Data
+		// a file which doesn't fit in the current content starts a new one
+		if len(current) > 0 && currentSize+overhead+len(data) > MaxContentSize {
+			flush()
+		}
+		for {
+			partSize := MaxContentSize - currentSize - overhead
+			if partSize > len(data) {
+				partSize = len(data)
+			}
+			current = append(current, WebsitePart{Path: file.Path, Data: data[:partSize]})
+			currentSize += overhead + partSize
+			data = data[partSize:]
+			if data == "" {
+				break
+			}
+			flush()
+		}
+	}
+	if len(current) > 0 {
+		flush()
+	}
+	return contents, nil
+}
+
+// WebsiteContent returns the content of a hosting transaction holding the given parts of files
+func WebsiteContent(parts []WebsitePart) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("{")
+	for i, part := range parts {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		pathBytes, err := json.Marshal(part.Path)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(pathBytes)
+		b.WriteString(":\"")
+		b.WriteString(part.Data)
+		b.WriteString("\"")
+	}
+	b.WriteString("}")
+	return b.Bytes(), nil
+}
+
+// AddWebsiteSslKey adds the ownership letting the nodes read the private key of the SSL certificate of the website
+func AddWebsiteSslKey(transaction *archethic.TransactionBuilder, sslKey []byte, storageNouncePublicKey string) error {
+	storageNouncePublicKeyBytes, err := hex.DecodeString(storageNouncePublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid storage nonce public key: %w", err)
+	}
+	secretKey := make([]byte, 32)
+	rand.Read(secretKey)
+	cipher, err := archethic.AesEncrypt(sslKey, secretKey)
+	if err != nil {
+		return err
+	}
+	encryptedSecretKey, err := archethic.EcEncrypt(secretKey, storageNouncePublicKeyBytes)
+	if err != nil {
+		return err
+	}
+	transaction.AddOwnership(cipher, []archethic.AuthorizedKey{{
+		PublicKey:          storageNouncePublicKeyBytes,
+		EncryptedSecretKey: encryptedSecretKey,
+	}})
+	return nil
+}
+
+// WebsiteURL returns the URL of the website hosted on the chain of the genesis address
+func WebsiteURL(endpoint string, genesisAddress string) string {
+	return strings.TrimRight(endpoint, "/") + "/api/web_hosting/" + strings.ToUpper(genesisAddress) + "/"
+}
+
+// HostedWebsite is the website currently deployed on a chain: the manifest held by the last transaction of the chain
+// and the ownerships of this transaction (holding the key of the SSL certificate)
+type HostedWebsite struct {
+	Manifest   WebsiteManifest
+	Ownerships []archethic.Ownership
+}
+
+// GetHostedWebsite returns the website deployed on the chain of the address, or nil if the chain doesn't have any transaction yet
+func GetHostedWebsite(endpoint string, address string) (*HostedWebsite, error) {
+	var result struct {
+		LastTransaction struct {
+			Type string `json:"type"`
+			Data struct {
+				Content    string `json:"content"`
+				Ownerships []struct {
+					Secret               string `json:"secret"`
+					AuthorizedPublicKeys []struct {
+						PublicKey          string `json:"publicKey"`
+						EncryptedSecretKey string `json:"encryptedSecretKey"`
+					} `json:"authorizedPublicKeys"`
+				} `json:"ownerships"`
+			} `json:"data"`
+		} `json:"lastTransaction"`
+	}
+	query := `query($address: Address!) {
+  lastTransaction(address: $address) {
+    type
+    data {
+      content
+      ownerships { secret authorizedPublicKeys { publicKey encryptedSecretKey } }
+    }
+  }
+}`
+	err := queryGraphql(endpoint, query, map[string]interface{}{"address": address}, &result)
+	if graphqlErr, ok := err.(GraphqlError); ok && graphqlErr.notFound() {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tx := result.LastTransaction
+	website := &HostedWebsite{}
+	if tx.Type != "hosting" || json.Unmarshal([]byte(tx.Data.Content), &website.Manifest) != nil || website.Manifest.AewebVersion == 0 {
+		return nil, errors.New("the last transaction of the chain is not the manifest of a website")
+	}
+	if website.Manifest.MetaData == nil {
+		website.Manifest.MetaData = map[string]WebsiteFileMeta{}
+	}
+	for _, ownership := range tx.Data.Ownerships {
+		secret, err := hex.DecodeString(ownership.Secret)
+		if err != nil {
+			return nil, err
+		}
+		authorizedKeys := make([]archethic.AuthorizedKey, len(ownership.AuthorizedPublicKeys))
+		for i, authorizedKey := range ownership.AuthorizedPublicKeys {
+			publicKey, err := hex.DecodeString(authorizedKey.PublicKey)
+			if err != nil {
+				return nil, err
+			}
+			encryptedSecretKey, err := hex.DecodeString(authorizedKey.EncryptedSecretKey)
+			if err != nil {
+				return nil, err
+			}
+			authorizedKeys[i] = archethic.AuthorizedKey{PublicKey: publicKey, EncryptedSecretKey: encryptedSecretKey}
+		}
+		website.Ownerships = append(website.Ownerships, archethic.Ownership{Secret: secret, AuthorizedKeys: authorizedKeys})
+	}
+	return website, nil
+}